@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/headless"
+	"github.com/daplf/go-tetris/io/tbp"
+)
+
+// result is the outcome of one simulated game
+type result struct {
+	Seed  int64          `json:"seed"`
+	Stats headless.Stats `json:"stats"`
+}
+
+func main() {
+	games := flag.Int("games", 1, "number of games to simulate")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the first game (each next game adds one)")
+	parallel := flag.Int("parallel", runtime.NumCPU(), "number of games simulated at the same time")
+	script := flag.String("script", "", "file with the moves to make, one per line (random moves if empty)")
+	bot := flag.String("bot", "", "command running a Tetris Bot Protocol bot to play the games (one per parallel game)")
+	moves := flag.Float64("moves", 0.1, "probability of making a random move on each update")
+	limit := flag.Duration("limit", time.Hour, "simulated time after which a game is stopped (0 for no limit)")
+	randomizer := flag.String("randomizer", game.RandomizerPure, "piece randomizer (Pure, Bag or History)")
+	width := flag.Int("width", board.DefaultWidth, "board width")
+	height := flag.Int("height", board.DefaultHeight, "board height")
+	jsonOutput := flag.Bool("json", false, "print the statistics of every game as JSON lines")
+	flag.Parse()
+
+	if *games < 1 || *parallel < 1 {
+		fmt.Fprintln(os.Stderr, "games and parallel must be at least 1")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var scriptMoves []game.Move
+
+	if *script != "" {
+		file, err := os.Open(*script)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		scriptMoves, err = headless.ParseScript(file)
+		file.Close()
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	config := game.DefaultConfig()
+	config.Randomizer = *randomizer
+	config.Width = board.Size(*width)
+	config.Height = board.Size(*height)
+
+	err := config.Validate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	seeds := make(chan int64)
+	results := make(chan result)
+	workers := sync.WaitGroup{}
+
+	for i := 0; i < *parallel; i++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			var player *tbp.Player
+
+			if *bot != "" {
+				botPlayer, err := startBot(*bot)
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "could not start the bot:", err)
+					os.Exit(1)
+				}
+				defer botPlayer.Close()
+
+				player = tbp.CreatePlayer(botPlayer)
+			}
+
+			for seed := range seeds {
+				input := headless.RandomInput(rand.New(rand.NewSource(seed)), *moves)
+				if scriptMoves != nil {
+					input = headless.ScriptedInput(scriptMoves)
+				}
+				if player != nil {
+					input = player.Input
+				}
+
+				config := config
+				config.Seed = seed
+
+				runner := headless.CreateRunner(config, input)
+				results <- result{Seed: seed, Stats: runner.Run(*limit)}
+
+				if player != nil && player.Err() != nil {
+					fmt.Fprintln(os.Stderr, "bot stopped playing:", player.Err())
+					os.Exit(1)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < *games; i++ {
+			seeds <- *seed + int64(i)
+		}
+
+		close(seeds)
+		workers.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	summary := summary{}
+	encoder := json.NewEncoder(os.Stdout)
+
+	for result := range results {
+		summary.add(result.Stats)
+
+		if *jsonOutput {
+			encoder.Encode(result)
+		}
+	}
+
+	if !*jsonOutput {
+		summary.print(time.Since(start))
+	}
+}
+
+// startBot runs a bot command (split on spaces), passing its errors through
+func startBot(command string) (*tbp.Bot, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, errors.New("empty bot command")
+	}
+
+	botCommand := exec.Command(fields[0], fields[1:]...)
+	botCommand.Stderr = os.Stderr
+
+	return tbp.CreateBot(botCommand)
+}
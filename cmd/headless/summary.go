@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/daplf/go-tetris/game/headless"
+)
+
+// summary aggregates the statistics of many games
+type summary struct {
+	games     int
+	score     int
+	bestScore int
+	lines     int
+	bestLines int
+	pieces    int
+	clears    [5]int
+	tSpins    int
+	toppedOut int
+	timedOut  int
+	simulated time.Duration
+}
+
+// add adds the statistics of a game to the summary
+func (summary *summary) add(stats headless.Stats) {
+	summary.games++
+	summary.score += stats.Score
+	summary.lines += stats.Lines
+	summary.pieces += stats.Pieces
+	summary.tSpins += stats.TSpins
+	summary.simulated += stats.Duration
+
+	for lines, count := range stats.Clears {
+		summary.clears[lines] += count
+	}
+
+	if stats.Score > summary.bestScore {
+		summary.bestScore = stats.Score
+	}
+
+	if stats.Lines > summary.bestLines {
+		summary.bestLines = stats.Lines
+	}
+
+	if stats.ToppedOut {
+		summary.toppedOut++
+	}
+
+	if stats.TimedOut {
+		summary.timedOut++
+	}
+}
+
+// print prints the summary
+func (summary *summary) print(wall time.Duration) {
+	if summary.games == 0 {
+		fmt.Println("no games simulated")
+		return
+	}
+
+	games := float64(summary.games)
+
+	fmt.Printf("games       %d (%d topped out, %d timed out)\n", summary.games, summary.toppedOut, summary.timedOut)
+	fmt.Printf("score       %.1f average, %d best\n", float64(summary.score)/games, summary.bestScore)
+	fmt.Printf("lines       %.1f average, %d best\n", float64(summary.lines)/games, summary.bestLines)
+	fmt.Printf("pieces      %.1f average\n", float64(summary.pieces)/games)
+	fmt.Printf("clears      %d singles, %d doubles, %d triples, %d tetrises\n",
+		summary.clears[1], summary.clears[2], summary.clears[3], summary.clears[4])
+	fmt.Printf("t-spins     %d\n", summary.tSpins)
+	fmt.Printf("simulated   %s in %s\n", summary.simulated.Round(time.Second), wall.Round(time.Millisecond))
+}
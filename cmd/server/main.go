@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/io/network"
+)
+
+func main() {
+	address := flag.String("addr", ":"+network.DefaultPort, "address to listen on")
+	randomizer := flag.String("randomizer", game.RandomizerBag, "piece randomizer used in every match (Pure, Bag or History)")
+	flag.Parse()
+
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("listening on", listener.Addr())
+
+	server := network.CreateServer(func() game.Config {
+		config := game.DefaultConfig()
+		config.Randomizer = *randomizer
+		config.Seed = time.Now().UnixNano()
+
+		return config
+	})
+
+	err = server.Serve(listener)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/ai"
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/headless"
+)
+
+// job is one game to play with a candidate's weights
+type job struct {
+	candidate int
+	seed      int64
+}
+
+// score is the number of lines a candidate cleared in one game
+type score struct {
+	candidate int
+	lines     int
+}
+
+func main() {
+	size := flag.Int("population", 100, "number of weight sets in each generation")
+	generations := flag.Int("generations", 20, "number of generations to evolve")
+	games := flag.Int("games", 10, "number of games played by each weight set in each generation")
+	pieces := flag.Int("pieces", 500, "number of pieces after which a game is stopped")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the evolution (the seeds of the games are drawn from it)")
+	parallel := flag.Int("parallel", runtime.NumCPU(), "number of games played at the same time")
+	mutation := flag.Float64("mutation", 0.05, "probability of mutating each new weight set")
+	randomizer := flag.String("randomizer", game.RandomizerPure, "piece randomizer (Pure, Bag or History)")
+	width := flag.Int("width", board.DefaultWidth, "board width")
+	height := flag.Int("height", board.DefaultHeight, "board height")
+	gravity := flag.Bool("gravity", false, "let pieces fall with the guideline gravity (otherwise they only move when the AI moves them)")
+	out := flag.String("out", "weights.json", "file the best weights are written to after each generation")
+	flag.Parse()
+
+	if *size < 2 || *games < 1 || *pieces < 1 || *parallel < 1 {
+		fmt.Fprintln(os.Stderr, "the population needs at least 2 weight sets, and games, pieces and parallel must be positive")
+		os.Exit(1)
+	}
+
+	config := game.DefaultConfig()
+	config.Randomizer = *randomizer
+	config.Width = board.Size(*width)
+	config.Height = board.Size(*height)
+
+	err := config.Validate()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if !*gravity {
+		config.GravityCurve = []time.Duration{time.Hour}
+	}
+
+	random := rand.New(rand.NewSource(*seed))
+	population := createPopulation(random, *size)
+
+	for generation := 1; generation <= *generations; generation++ {
+		start := time.Now()
+		seeds := make([]int64, *games)
+
+		for i := range seeds {
+			seeds[i] = random.Int63()
+		}
+
+		evaluate(population, config, seeds, *pieces, *parallel)
+
+		best := population.best()
+		fmt.Printf("generation %d: best %.1f lines, average %.1f lines (%s)\n", generation, best.fitness, population.averageFitness(), time.Since(start).Round(time.Millisecond))
+		fmt.Printf("  holes %.4f, aggregate height %.4f, bumpiness %.4f, lines %.4f\n", best.weights.Holes, best.weights.AggregateHeight, best.weights.Bumpiness, best.weights.Lines)
+
+		err = best.weights.Save(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "could not save the weights:", err)
+			os.Exit(1)
+		}
+
+		if generation < *generations {
+			population = population.evolve(random, *mutation)
+		}
+	}
+}
+
+// evaluate plays every candidate of a population on the same games, setting its fitness to the average lines cleared
+func evaluate(population population, config game.Config, seeds []int64, pieces int, parallel int) {
+	jobs := make(chan job)
+	scores := make(chan score)
+	workers := sync.WaitGroup{}
+
+	for i := 0; i < parallel; i++ {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			for job := range jobs {
+				scores <- score{candidate: job.candidate, lines: play(population[job.candidate].weights, config, job.seed, pieces)}
+			}
+		}()
+	}
+
+	go func() {
+		for candidate := range population {
+			for _, seed := range seeds {
+				jobs <- job{candidate: candidate, seed: seed}
+			}
+		}
+
+		close(jobs)
+		workers.Wait()
+		close(scores)
+	}()
+
+	lines := make([]int, len(population))
+
+	for score := range scores {
+		lines[score.candidate] += score.lines
+	}
+
+	for i, candidate := range population {
+		candidate.fitness = float64(lines[i]) / float64(len(seeds))
+	}
+}
+
+// play lets the AI play a game until it tops out or places enough pieces, returning the lines it cleared
+func play(weights ai.Weights, config game.Config, seed int64, pieces int) int {
+	config.Seed = seed
+
+	player := ai.CreatePlayer(ai.CreateAI(weights), nil)
+	runner := headless.CreateRunner(config, player.Input)
+
+	for runner.Game().IsRunning() && runner.Stats().Pieces < pieces {
+		runner.Step()
+	}
+
+	return runner.Stats().Lines
+}
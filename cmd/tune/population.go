@@ -0,0 +1,166 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/daplf/go-tetris/game/ai"
+)
+
+const (
+	// tournamentShare is the share of the population drawn for each tournament
+	tournamentShare = 0.1
+
+	// offspringShare is the share of the population replaced by offspring in each generation
+	offspringShare = 0.3
+
+	// mutationStep is the largest change a mutation makes to a weight (before normalization)
+	mutationStep = 0.2
+)
+
+// candidate is a weight set and the average number of lines it cleared
+type candidate struct {
+	weights ai.Weights
+	fitness float64
+}
+
+// population holds the candidates of a generation
+type population []*candidate
+
+// createPopulation creates a population of random weight sets
+func createPopulation(random *rand.Rand, size int) population {
+	population := make(population, size)
+
+	for i := range population {
+		vector := [4]float64{}
+
+		for j := range vector {
+			vector[j] = random.Float64()*2 - 1
+		}
+
+		population[i] = &candidate{weights: fromVector(vector)}
+	}
+
+	return population
+}
+
+// best returns the fittest candidate
+func (population population) best() *candidate {
+	best := population[0]
+
+	for _, candidate := range population {
+		if candidate.fitness > best.fitness {
+			best = candidate
+		}
+	}
+
+	return best
+}
+
+// averageFitness returns the average fitness of the population
+func (population population) averageFitness() float64 {
+	total := 0.0
+
+	for _, candidate := range population {
+		total += candidate.fitness
+	}
+
+	return total / float64(len(population))
+}
+
+// evolve breeds the next generation: offspring of tournament winners replace the least fit candidates
+func (population population) evolve(random *rand.Rand, mutation float64) population {
+	next := append([]*candidate{}, population...)
+	sort.SliceStable(next, func(i, j int) bool {
+		return next[i].fitness > next[j].fitness
+	})
+
+	offspring := int(float64(len(next)) * offspringShare)
+	if offspring < 1 {
+		offspring = 1
+	}
+
+	children := make([]*candidate, offspring)
+
+	for i := range children {
+		first, second := population.tournament(random)
+		children[i] = crossover(first, second)
+
+		if random.Float64() < mutation {
+			children[i].mutate(random)
+		}
+	}
+
+	copy(next[len(next)-offspring:], children)
+
+	return next
+}
+
+// tournament draws a few candidates at random and returns the two fittest
+func (population population) tournament(random *rand.Rand) (*candidate, *candidate) {
+	size := int(float64(len(population)) * tournamentShare)
+	if size < 2 {
+		size = 2
+	}
+
+	drawn := []*candidate{}
+	for _, i := range random.Perm(len(population))[:size] {
+		drawn = append(drawn, population[i])
+	}
+
+	sort.SliceStable(drawn, func(i, j int) bool {
+		return drawn[i].fitness > drawn[j].fitness
+	})
+
+	return drawn[0], drawn[1]
+}
+
+// crossover averages the weights of two candidates, giving more say to the fitter one
+func crossover(first, second *candidate) *candidate {
+	firstShare, secondShare := first.fitness, second.fitness
+	if firstShare+secondShare == 0 {
+		firstShare, secondShare = 1, 1
+	}
+
+	firstVector, secondVector := toVector(first.weights), toVector(second.weights)
+	vector := [4]float64{}
+
+	for i := range vector {
+		vector[i] = firstVector[i]*firstShare + secondVector[i]*secondShare
+	}
+
+	return &candidate{weights: fromVector(vector)}
+}
+
+// mutate changes one of the candidate's weights by a random amount
+func (candidate *candidate) mutate(random *rand.Rand) {
+	vector := toVector(candidate.weights)
+	vector[random.Intn(len(vector))] += (random.Float64()*2 - 1) * mutationStep
+	candidate.weights = fromVector(vector)
+}
+
+// toVector returns the weights as a vector
+func toVector(weights ai.Weights) [4]float64 {
+	return [4]float64{weights.Holes, weights.AggregateHeight, weights.Bumpiness, weights.Lines}
+}
+
+// fromVector returns the weights of a vector, normalized to unit length (only the ratios between weights matter)
+func fromVector(vector [4]float64) ai.Weights {
+	length := 0.0
+	for _, value := range vector {
+		length += value * value
+	}
+
+	length = math.Sqrt(length)
+	if length == 0 {
+		length = 1
+	}
+
+	return ai.Weights{
+		Holes:           vector[0] / length,
+		AggregateHeight: vector[1] / length,
+		Bumpiness:       vector[2] / length,
+		Lines:           vector[3] / length,
+	}
+}
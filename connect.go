@@ -0,0 +1,89 @@
+//go:build !nogl
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+	"github.com/daplf/go-tetris/io/inputProcessor"
+	"github.com/daplf/go-tetris/io/network"
+	"github.com/daplf/go-tetris/io/renderer"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// runConnect plays a match against another player on a server
+func runConnect(args []string) {
+	flags := flag.NewFlagSet("connect", flag.ExitOnError)
+	room := flags.String("room", "default", "room to join")
+	name := flags.String("name", os.Getenv("USER"), "name shown to the opponent")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go-tetris connect [-room name] [-name name] [host:port]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	address := "localhost:" + network.DefaultPort
+	if flags.NArg() > 0 {
+		address = flags.Arg(0)
+	}
+
+	client, err := network.Connect(address, *room, *name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer client.Close()
+
+	pixelgl.Run(func() {
+		renderer := renderer.CreateVersusRenderer()
+		inputProcessor := inputProcessor.CreateInputProcessor(renderer.Window())
+
+		for !client.Ended() {
+			move := inputProcessor.GetInput()
+			if move == game.Closed {
+				return
+			}
+
+			client.Update(move)
+
+			if client.Started() {
+				renderer.DrawVersus(clientSnapshots(client), clientWinner(client))
+			} else {
+				renderer.DrawPrompt(fmt.Sprintf("Waiting for an opponent in room %q...", *room))
+			}
+		}
+
+		for client.Started() && !renderer.Window().Closed() {
+			renderer.DrawVersus(clientSnapshots(client), clientWinner(client))
+		}
+	})
+
+	if client.Err() != nil {
+		fmt.Fprintln(os.Stderr, client.Err())
+	}
+
+	if client.Desynced() {
+		fmt.Fprintln(os.Stderr, "the opponent's game diverged from its replica")
+	}
+}
+
+// clientSnapshots takes snapshots of the player's game (on the left) and the opponent's replica (on the right)
+func clientSnapshots(client *network.Client) [versus.Players]game.Snapshot {
+	return [versus.Players]game.Snapshot{client.Game().Snapshot(), client.Opponent().Snapshot()}
+}
+
+// clientWinner converts the winner of a match to the side of the screen it is drawn on
+func clientWinner(client *network.Client) int {
+	switch client.Winner() {
+	case versus.NoWinner:
+		return versus.NoWinner
+	case client.Player():
+		return 0
+	default:
+		return 1
+	}
+}
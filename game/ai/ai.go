@@ -0,0 +1,195 @@
+package ai
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/placement"
+)
+
+// Weights scale the features of a board after a placement; the placement with the highest total wins.
+// Features that make a board worse (holes, height, bumpiness) should get negative weights.
+type Weights struct {
+	Holes           float64 `json:"holes"`
+	AggregateHeight float64 `json:"aggregateHeight"`
+	Bumpiness       float64 `json:"bumpiness"`
+	Lines           float64 `json:"lines"`
+}
+
+// features describes a board after a placement.
+// Holes counts the empty squares below the top of their column, AggregateHeight adds up the heights of the columns,
+// Bumpiness adds up the height differences between neighbouring columns and Lines counts the rows the placement cleared.
+type features struct {
+	Holes           int
+	AggregateHeight int
+	Bumpiness       int
+	Lines           int
+}
+
+// AI picks where to place pieces by scoring every placement they can reach
+type AI struct {
+	weights Weights
+}
+
+// DefaultWeights returns weights that clear lines steadily on the default board
+func DefaultWeights() Weights {
+	return Weights{
+		Holes:           -0.35663,
+		AggregateHeight: -0.510066,
+		Bumpiness:       -0.184483,
+		Lines:           0.760666,
+	}
+}
+
+// LoadWeights reads weights from a file (as written by Save)
+func LoadWeights(path string) (Weights, error) {
+	weights := Weights{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return weights, err
+	}
+
+	err = json.Unmarshal(data, &weights)
+
+	return weights, err
+}
+
+// Save writes weights to a file
+func (weights Weights) Save(path string) error {
+	data, err := json.MarshalIndent(weights, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// CreateAI creates an AI scoring placements with the given weights
+func CreateAI(weights Weights) *AI {
+	return &AI{
+		weights: weights,
+	}
+}
+
+// Weights returns the weights the AI scores placements with
+func (ai *AI) Weights() Weights {
+	return ai.weights
+}
+
+// Best returns the best placement a piece on a board can reach (false if it can't go anywhere)
+func (ai *AI) Best(original *board.Board, currentPiece *piece.Piece, rotationSystem piece.RotationSystem) (placement.Placement, bool) {
+	grid := createGrid(original, currentPiece)
+	best := placement.Placement{}
+	bestScore := 0.0
+	found := false
+
+	for _, candidate := range placement.FindOnBoard(original, currentPiece, rotationSystem) {
+		score := ai.score(measure(grid, candidate))
+
+		if !found || score > bestScore {
+			best = candidate
+			bestScore = score
+			found = true
+		}
+	}
+
+	return best, found
+}
+
+// score weighs the features of a board
+func (ai *AI) score(measured features) float64 {
+	return ai.weights.Holes*float64(measured.Holes) +
+		ai.weights.AggregateHeight*float64(measured.AggregateHeight) +
+		ai.weights.Bumpiness*float64(measured.Bumpiness) +
+		ai.weights.Lines*float64(measured.Lines)
+}
+
+// measure returns the features of a board (given as its occupied squares, bottom row first) once a placement locks
+func measure(grid [][]bool, candidate placement.Placement) features {
+	measured := features{}
+	rows := make([][]bool, 0, len(grid))
+
+	for y, row := range grid {
+		placed := append([]bool{}, row...)
+		full := true
+
+		for _, square := range candidate.Squares {
+			if square.Y == y {
+				placed[square.X] = true
+			}
+		}
+
+		for _, occupied := range placed {
+			full = full && occupied
+		}
+
+		if full {
+			measured.Lines++
+		} else {
+			rows = append(rows, placed)
+		}
+	}
+
+	if len(rows) == 0 {
+		return measured
+	}
+
+	previousHeight := 0
+
+	for x := range rows[0] {
+		height := 0
+
+		for y := len(rows) - 1; y >= 0 && height == 0; y-- {
+			if rows[y][x] {
+				height = y + 1
+			}
+		}
+
+		for y := 0; y < height; y++ {
+			if !rows[y][x] {
+				measured.Holes++
+			}
+		}
+
+		measured.AggregateHeight += height
+
+		if x > 0 {
+			measured.Bumpiness += abs(height - previousHeight)
+		}
+
+		previousHeight = height
+	}
+
+	return measured
+}
+
+// createGrid returns the occupied squares of a board (bottom row first), leaving out a piece
+func createGrid(original *board.Board, currentPiece *piece.Piece) [][]bool {
+	grid := make([][]bool, original.Height())
+
+	for y, row := range original.Squares() {
+		grid[y] = make([]bool, original.Width())
+
+		for x, square := range row {
+			grid[y][x] = square != nil
+		}
+	}
+
+	for _, pieceBlock := range currentPiece.Blocks() {
+		grid[pieceBlock.Y()][pieceBlock.X()] = false
+	}
+
+	return grid
+}
+
+// abs returns the absolute value of an integer
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
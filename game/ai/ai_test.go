@@ -0,0 +1,62 @@
+package ai
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/placement"
+)
+
+func TestMeasure(t *testing.T) {
+	// rows are written top first, # for an occupied square
+	tests := []struct {
+		name     string
+		rows     []string
+		squares  []game.Square
+		measured features
+	}{
+		{"empty board", []string{"....", "...."}, []game.Square{{X: 0, Y: 0}}, features{AggregateHeight: 1, Bumpiness: 1}},
+		{"hole under a block", []string{"....", "#..."}, []game.Square{{X: 1, Y: 1}}, features{Holes: 1, AggregateHeight: 3, Bumpiness: 3}},
+		{"cleared row", []string{"....", "##.#"}, []game.Square{{X: 2, Y: 0}}, features{Lines: 1}},
+		{"rows above a clear fall", []string{"#...", "##.#"}, []game.Square{{X: 2, Y: 0}}, features{Lines: 1, AggregateHeight: 1, Bumpiness: 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grid := make([][]bool, len(test.rows))
+
+			for i, row := range test.rows {
+				y := len(test.rows) - 1 - i
+				grid[y] = make([]bool, len(row))
+
+				for x, square := range row {
+					grid[y][x] = square == '#'
+				}
+			}
+
+			measured := measure(grid, placement.Placement{Squares: test.squares})
+			if measured != test.measured {
+				t.Fatalf("got %+v, expected %+v", measured, test.measured)
+			}
+		})
+	}
+}
+
+func TestWeightsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "weights.json")
+
+	err := DefaultWeights().Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weights, err := LoadWeights(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if weights != DefaultWeights() {
+		t.Fatalf("loaded %+v, expected %+v", weights, DefaultWeights())
+	}
+}
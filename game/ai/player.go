@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/placement"
+)
+
+// Player plays a game with the AI's placements, making one move per update
+type Player struct {
+	ai    *AI
+	game  *game.Game
+	piece *piece.Piece
+	plan  *placement.Plan
+}
+
+// Hinter suggests where to place the current piece of a game, working it out once per piece
+type Hinter struct {
+	ai      *AI
+	piece   *piece.Piece
+	squares []game.Square
+}
+
+// CreatePlayer creates a player for a game (which can be nil if the player is only used through Input)
+func CreatePlayer(ai *AI, current *game.Game) *Player {
+	return &Player{
+		ai:   ai,
+		game: current,
+	}
+}
+
+// GetInput returns the move to make on the next update of the player's game
+func (player *Player) GetInput() game.Move {
+	return player.Input(player.game)
+}
+
+// Input returns the move to make on the next update of a game (it can be used as a headless.Input)
+func (player *Player) Input(current *game.Game) game.Move {
+	if current == nil || !current.IsRunning() || current.IsPaused() || current.CurrentPiece() == nil {
+		return game.NoMove
+	}
+
+	if current.CurrentPiece() != player.piece {
+		player.piece = current.CurrentPiece()
+		player.choose(current)
+	}
+
+	if player.plan == nil {
+		return game.NoMove
+	}
+
+	move, ok := player.plan.NextMove(current)
+	if !ok {
+		// gravity took the placement out of reach, so the piece goes to the best one left
+		player.choose(current)
+
+		if player.plan == nil {
+			return game.HardDrop
+		}
+
+		move, ok = player.plan.NextMove(current)
+		if !ok {
+			return game.HardDrop
+		}
+	}
+
+	return move
+}
+
+// choose plans the moves to the best placement the current piece can reach from where it is
+func (player *Player) choose(current *game.Game) {
+	player.plan = nil
+
+	best, ok := player.ai.Best(current.Board(), current.CurrentPiece(), current.RotationSystem())
+	if ok {
+		player.plan = placement.CreatePlan(best)
+	}
+}
+
+// CreateHinter creates a hinter suggesting the AI's placements
+func CreateHinter(ai *AI) *Hinter {
+	return &Hinter{
+		ai: ai,
+	}
+}
+
+// Hint returns the squares where the AI would place the current piece (none if the game has no piece)
+func (hinter *Hinter) Hint(current *game.Game) []game.Square {
+	if !current.IsRunning() || current.CurrentPiece() == nil {
+		return []game.Square{}
+	}
+
+	if current.CurrentPiece() != hinter.piece {
+		hinter.piece = current.CurrentPiece()
+		hinter.squares = []game.Square{}
+
+		best, ok := hinter.ai.Best(current.Board(), current.CurrentPiece(), current.RotationSystem())
+		if ok {
+			hinter.squares = best.Squares
+		}
+	}
+
+	return hinter.squares
+}
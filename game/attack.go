@@ -0,0 +1,49 @@
+package game
+
+const (
+	backToBackAttack = 1
+
+	perfectClearAttack = 10
+)
+
+var (
+	lineAttacks      = []int{0, 0, 1, 2, 4}
+	tSpinAttacks     = []int{0, 2, 4, 6}
+	tSpinMiniAttacks = []int{0, 0, 1}
+	comboAttacks     = []int{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 4, 5}
+)
+
+// Attack returns the number of garbage lines a lock sends to an opponent, before cancelling queued garbage (guideline attack table)
+func Attack(result ClearResult) int {
+	if result.Lines == 0 {
+		return 0
+	}
+
+	attack := 0
+
+	switch result.TSpin {
+	case TSpinFull:
+		attack = tSpinAttacks[clamp(result.Lines, len(tSpinAttacks)-1)]
+		break
+	case TSpinMini:
+		attack = tSpinMiniAttacks[clamp(result.Lines, len(tSpinMiniAttacks)-1)]
+		break
+	default:
+		attack = lineAttacks[clamp(result.Lines, len(lineAttacks)-1)]
+		break
+	}
+
+	if result.BackToBack {
+		attack += backToBackAttack
+	}
+
+	if result.Combo > 0 {
+		attack += comboAttacks[clamp(result.Combo, len(comboAttacks)-1)]
+	}
+
+	if result.PerfectClear {
+		attack += perfectClearAttack
+	}
+
+	return attack
+}
@@ -0,0 +1,36 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/daplf/go-tetris/game/piece"
+)
+
+func TestAttack(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ClearResult
+		attack int
+	}{
+		{"nothing", ClearResult{}, 0},
+		{"single", ClearResult{Lines: 1}, 0},
+		{"double", ClearResult{Lines: 2}, 1},
+		{"tetris", ClearResult{Lines: 4}, 4},
+		{"back-to-back tetris", ClearResult{Lines: 4, BackToBack: true}, 5},
+		{"T-spin double", ClearResult{Lines: 2, TSpin: TSpinFull}, 4},
+		{"T-spin mini single", ClearResult{Lines: 1, TSpin: TSpinMini}, 0},
+		{"single in a combo of 4", ClearResult{Lines: 1, Combo: 4}, 2},
+		{"single in a long combo", ClearResult{Lines: 1, Combo: 30}, 5},
+		{"perfect clear double", ClearResult{Lines: 2, PerfectClear: true, PieceType: piece.PieceO}, 11},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			attack := Attack(test.result)
+
+			if attack != test.attack {
+				t.Fatalf("got %d lines, expected %d", attack, test.attack)
+			}
+		})
+	}
+}
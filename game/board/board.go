@@ -1,6 +1,9 @@
 package board
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/daplf/go-tetris/game/piece"
 	"github.com/daplf/go-tetris/game/piece/block"
 )
@@ -9,8 +12,11 @@ import (
 type Size = int
 
 const (
-	boardWidth  = 10
-	boardHeight = 20
+	// DefaultWidth is the width of the default board
+	DefaultWidth = 10
+
+	// DefaultHeight is the height of the default board
+	DefaultHeight = 20
 )
 
 // Board holds the board logic
@@ -20,6 +26,13 @@ type Board struct {
 	squares [][]*block.Block
 }
 
+// boardState is the JSON representation of a board, with the block type of every square (bottom row first)
+type boardState struct {
+	Width   Size           `json:"width"`
+	Height  Size           `json:"height"`
+	Squares [][]block.Type `json:"squares"`
+}
+
 // Width returns the width of the board
 func (board *Board) Width() Size {
 	return board.width
@@ -37,11 +50,11 @@ func (board *Board) Squares() [][]*block.Block {
 
 // CreateBoard creates a new default board
 func CreateBoard() *Board {
-	squares := initSquares(boardWidth, boardHeight)
+	squares := initSquares(DefaultWidth, DefaultHeight)
 
 	return &Board{
-		width:   boardWidth,
-		height:  boardHeight,
+		width:   DefaultWidth,
+		height:  DefaultHeight,
 		squares: squares,
 	}
 }
@@ -75,6 +88,15 @@ func (board *Board) SetSquares(blocks []*block.Block) {
 	}
 }
 
+// ClearSquares removes some blocks from the board
+func (board *Board) ClearSquares(blocks []*block.Block) {
+	for _, block := range blocks {
+		if board.squares[block.Y()][block.X()] == block {
+			board.squares[block.Y()][block.X()] = nil
+		}
+	}
+}
+
 // MoveBlocksDown moves blocks down if possible
 func (board *Board) MoveBlocksDown(blocks []*block.Block) bool {
 	movePossible := true
@@ -195,43 +217,115 @@ func (board *Board) MoveBlocksLeft(blocks []*block.Block) bool {
 	return movePossible
 }
 
-// RotateBlocksRight rotates blocks left if possible
-func (board *Board) RotateBlocksRight(blocks []*block.Block, state piece.State) piece.State {
+// DropDistance returns how many rows some blocks could fall before landing, without moving them
+func (board *Board) DropDistance(blocks []*block.Block) Size {
+	distance := 0
+
+	for board.canPlace(blocks, 0, -(distance + 1)) {
+		distance++
+	}
+
+	return distance
+}
+
+// ProjectBlocksDown returns copies of some blocks placed where they would land if dropped straight down
+func (board *Board) ProjectBlocksDown(blocks []*block.Block) []*block.Block {
+	distance := board.DropDistance(blocks)
+	projection := make([]*block.Block, len(blocks))
+
+	for i, b := range blocks {
+		projection[i] = block.CreateBlock(b.X(), b.Y()-distance, b.Type())
+	}
+
+	return projection
+}
+
+// canPlace checks if some blocks fit on the board after being offset, ignoring the blocks themselves
+func (board *Board) canPlace(blocks []*block.Block, offsetX, offsetY block.Position) bool {
+	for _, block := range blocks {
+		x := block.X() + offsetX
+		y := block.Y() + offsetY
+
+		if x >= board.width || x < 0 || y >= board.height || y < 0 {
+			return false
+		}
+
+		if board.squares[y][x] != nil {
+			ok := false
+
+			for _, neighbour := range blocks {
+				if board.squares[y][x] == neighbour {
+					ok = true
+				}
+			}
+
+			if !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// RotateBlocksRight rotates blocks right if possible, returning the new state and the index of the kick used
+func (board *Board) RotateBlocksRight(blocks []*block.Block, state piece.State, rotationSystem piece.RotationSystem) (piece.State, int) {
 	newState := state + 1
 
-	if newState > 3 {
+	if newState >= piece.NumStates {
 		newState = piece.NormalState
 	}
 
-	return board.rotate(blocks, state, newState)
+	return board.rotate(blocks, state, newState, rotationSystem)
 }
 
-// RotateBlocksLeft rotates blocks left if possible
-func (board *Board) RotateBlocksLeft(blocks []*block.Block, state piece.State) piece.State {
+// RotateBlocksLeft rotates blocks left if possible, returning the new state and the index of the kick used
+func (board *Board) RotateBlocksLeft(blocks []*block.Block, state piece.State, rotationSystem piece.RotationSystem) (piece.State, int) {
 	newState := state - 1
 
 	if newState < 0 {
 		newState = piece.NumStates - 1
 	}
 
-	return board.rotate(blocks, state, newState)
+	return board.rotate(blocks, state, newState, rotationSystem)
 }
 
-// rotate rotates a block to a new position
-func (board *Board) rotate(blocks []*block.Block, state, newState piece.State) piece.State {
-	movePossible := true
-
+// rotate rotates a block to a new position, trying each of the rotation system's kicks in order
+func (board *Board) rotate(blocks []*block.Block, state, newState piece.State, rotationSystem piece.RotationSystem) (piece.State, int) {
 	blockType := blocks[0].Type()
 
-	oldCoords, newCoords := getOldAndNewCoords(blockType, state, newState)
+	oldCoords := rotationSystem.Coords(blockType, state)
+	newCoords := rotationSystem.Coords(blockType, newState)
+
+	for kickIndex, kick := range rotationSystem.Kicks(blockType, state, newState) {
+		if board.canRotate(blocks, oldCoords, newCoords, kick) {
+			for i := 0; i < len(blocks); i++ {
+				board.squares[blocks[i].Y()][blocks[i].X()] = nil
+			}
+
+			for i := 0; i < len(blocks); i++ {
+				newX := blocks[i].X() - (oldCoords[0][i] - newCoords[0][i]) + kick.X
+				newY := blocks[i].Y() - (oldCoords[1][i] - newCoords[1][i]) + kick.Y
+				blocks[i].SetX(newX)
+				blocks[i].SetY(newY)
+				board.squares[blocks[i].Y()][blocks[i].X()] = blocks[i]
+			}
 
+			return newState, kickIndex
+		}
+	}
+
+	return state, piece.NoKick
+}
+
+// canRotate checks if blocks can be rotated between two sets of coords after applying a kick
+func (board *Board) canRotate(blocks []*block.Block, oldCoords, newCoords [][]block.Position, kick piece.Kick) bool {
 	for i := range blocks {
-		newX := blocks[i].X() - (oldCoords[0][i] - newCoords[0][i])
-		newY := blocks[i].Y() - (oldCoords[1][i] - newCoords[1][i])
+		newX := blocks[i].X() - (oldCoords[0][i] - newCoords[0][i]) + kick.X
+		newY := blocks[i].Y() - (oldCoords[1][i] - newCoords[1][i]) + kick.Y
 
 		if newX >= board.width || newX < 0 || newY >= board.height || newY < 0 {
-			movePossible = false
-			break
+			return false
 		}
 
 		if board.squares[newY][newX] != nil {
@@ -244,72 +338,17 @@ func (board *Board) rotate(blocks []*block.Block, state, newState piece.State) p
 			}
 
 			if !ok {
-				movePossible = false
-				break
+				return false
 			}
 		}
 	}
 
-	if movePossible {
-		for i := 0; i < len(blocks); i++ {
-			board.squares[blocks[i].Y()][blocks[i].X()] = nil
-		}
-
-		for i := 0; i < len(blocks); i++ {
-			newX := blocks[i].X() - (oldCoords[0][i] - newCoords[0][i])
-			newY := blocks[i].Y() - (oldCoords[1][i] - newCoords[1][i])
-			blocks[i].SetX(newX)
-			blocks[i].SetY(newY)
-			board.squares[blocks[i].Y()][blocks[i].X()] = blocks[i]
-		}
-
-		state = newState
-	}
-
-	return state
-}
-
-// getOldAndNewCoords gets the old and new coords of the piece
-func getOldAndNewCoords(blockType block.Type, oldState, newState piece.State) ([][]block.Position, [][]block.Position) {
-	var oldCoords [][]block.Position
-	var newCoords [][]block.Position
-
-	switch blockType {
-	case piece.PieceI:
-		oldCoords = piece.PieceICoords[oldState]
-		newCoords = piece.PieceICoords[newState]
-		break
-	case piece.PieceJ:
-		oldCoords = piece.PieceJCoords[oldState]
-		newCoords = piece.PieceJCoords[newState]
-		break
-	case piece.PieceL:
-		oldCoords = piece.PieceLCoords[oldState]
-		newCoords = piece.PieceLCoords[newState]
-		break
-	case piece.PieceO:
-		oldCoords = piece.PieceOCoords[oldState]
-		newCoords = piece.PieceOCoords[newState]
-		break
-	case piece.PieceS:
-		oldCoords = piece.PieceSCoords[oldState]
-		newCoords = piece.PieceSCoords[newState]
-		break
-	case piece.PieceT:
-		oldCoords = piece.PieceTCoords[oldState]
-		newCoords = piece.PieceTCoords[newState]
-		break
-	case piece.PieceZ:
-		oldCoords = piece.PieceZCoords[oldState]
-		newCoords = piece.PieceZCoords[newState]
-		break
-	}
-
-	return oldCoords, newCoords
+	return true
 }
 
-// DestroyFullRows destroys full rows
-func (board *Board) DestroyFullRows() int {
+// DestroyFullRows destroys full rows, returning their indices
+func (board *Board) DestroyFullRows() []int {
+	rows := make([]int, 0)
 	fall := 0
 	for i := range board.squares {
 		full := true
@@ -324,6 +363,7 @@ func (board *Board) DestroyFullRows() int {
 			for j := range board.squares[i] {
 				board.squares[i][j] = nil
 			}
+			rows = append(rows, i)
 			fall++
 		} else {
 			oldI := i
@@ -345,5 +385,158 @@ func (board *Board) DestroyFullRows() int {
 		}
 	}
 
-	return fall
+	return rows
+}
+
+// InsertGarbage pushes every block up and fills the bottom rows with garbage, leaving a hole in one column.
+// It returns false if blocks were pushed out of the top of the board.
+func (board *Board) InsertGarbage(rows Size, hole block.Position) bool {
+	if rows > board.height {
+		rows = board.height
+	}
+
+	fits := true
+
+	for y := board.height - rows; y < board.height; y++ {
+		for _, square := range board.squares[y] {
+			if square != nil {
+				fits = false
+			}
+		}
+	}
+
+	for y := board.height - 1; y >= rows; y-- {
+		board.squares[y] = board.squares[y-rows]
+
+		for _, square := range board.squares[y] {
+			if square != nil {
+				square.SetY(y)
+			}
+		}
+	}
+
+	for y := 0; y < rows; y++ {
+		board.squares[y] = make([]*block.Block, board.width)
+
+		for x := range board.squares[y] {
+			if x != hole {
+				board.squares[y][x] = block.CreateBlock(x, y, piece.Garbage)
+			}
+		}
+	}
+
+	return fits
+}
+
+// IsOccupied checks if a square is taken (squares outside the board count as taken)
+func (board *Board) IsOccupied(x, y block.Position) bool {
+	if x >= board.width || x < 0 || y >= board.height || y < 0 {
+		return true
+	}
+
+	return board.squares[y][x] != nil
+}
+
+// IsEmpty checks if there are no blocks on the board
+func (board *Board) IsEmpty() bool {
+	for _, row := range board.squares {
+		for _, square := range row {
+			if square != nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Copy returns a board with the same blocks (copied, so moving them doesn't change the original)
+func (board *Board) Copy() *Board {
+	copied := CreateBoardWithDimensions(board.width, board.height)
+
+	for y, row := range board.squares {
+		for x, square := range row {
+			if square != nil {
+				copied.squares[y][x] = block.CreateBlock(x, y, square.Type())
+			}
+		}
+	}
+
+	return copied
+}
+
+// Equal checks if two boards have the same size and the same block type on every square
+func (board *Board) Equal(other *Board) bool {
+	if board.width != other.width || board.height != other.height {
+		return false
+	}
+
+	for y, row := range board.squares {
+		for x, square := range row {
+			otherSquare := other.squares[y][x]
+
+			if (square == nil) != (otherSquare == nil) {
+				return false
+			}
+
+			if square != nil && square.Type() != otherSquare.Type() {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// MarshalJSON stores the board as JSON
+func (board *Board) MarshalJSON() ([]byte, error) {
+	state := boardState{
+		Width:   board.width,
+		Height:  board.height,
+		Squares: make([][]block.Type, board.height),
+	}
+
+	for y, row := range board.squares {
+		state.Squares[y] = make([]block.Type, board.width)
+
+		for x, square := range row {
+			if square != nil {
+				state.Squares[y][x] = square.Type()
+			}
+		}
+	}
+
+	return json.Marshal(state)
+}
+
+// UnmarshalJSON restores a board stored with MarshalJSON
+func (board *Board) UnmarshalJSON(data []byte) error {
+	state := boardState{}
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return err
+	}
+
+	if len(state.Squares) != state.Height {
+		return fmt.Errorf("board has %d rows instead of %d", len(state.Squares), state.Height)
+	}
+
+	board.width = state.Width
+	board.height = state.Height
+	board.squares = initSquares(state.Width, state.Height)
+
+	for y, row := range state.Squares {
+		if len(row) != state.Width {
+			return fmt.Errorf("board row %d has %d squares instead of %d", y, len(row), state.Width)
+		}
+
+		for x, blockType := range row {
+			if blockType != piece.NoPiece {
+				board.squares[y][x] = block.CreateBlock(x, y, blockType)
+			}
+		}
+	}
+
+	return nil
 }
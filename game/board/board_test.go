@@ -0,0 +1,177 @@
+package board
+
+import (
+	"testing"
+
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// placePiece puts the blocks of a piece on the board, around an origin
+func placePiece(board *Board, rotationSystem piece.RotationSystem, pieceType block.Type, state piece.State, x, y block.Position) []*block.Block {
+	coords := rotationSystem.Coords(pieceType, state)
+	blocks := make([]*block.Block, len(coords[0]))
+
+	for i := range blocks {
+		blocks[i] = block.CreateBlock(x+coords[0][i], y+coords[1][i], pieceType)
+	}
+
+	board.SetSquares(blocks)
+
+	return blocks
+}
+
+func TestRotationKicks(t *testing.T) {
+	tests := []struct {
+		name           string
+		rotationSystem string
+		pieceType      block.Type
+		state          piece.State
+		x, y           block.Position
+		filled         [][2]block.Position
+		right          bool
+		kick           int
+		kickX, kickY   block.Position
+	}{
+		{"T in the open", piece.SRS, piece.PieceT, piece.NormalState, 4, 10, nil, true, 0, 0, 0},
+		{"T off the left wall", piece.SRS, piece.PieceT, piece.RightState, 0, 10, nil, true, 1, 1, 0},
+		{"T off the floor", piece.SRS, piece.PieceT, piece.NormalState, 4, 0, nil, true, 2, -1, 1},
+		{"T into a T-spin triple slot", piece.SRS, piece.PieceT, piece.NormalState, 4, 3, [][2]block.Position{{4, 2}, {3, 4}}, true, 4, -1, -2},
+		{"T rotating left in the open", piece.SRS, piece.PieceT, piece.NormalState, 4, 10, nil, false, 0, 0, 0},
+		{"I off the left wall", piece.SRS, piece.PieceI, piece.RightState, 0, 10, nil, true, 2, 2, 0},
+		{"I off the right wall", piece.SRS, piece.PieceI, piece.LeftState, 9, 10, nil, true, 2, -2, 0},
+		{"O never kicks", piece.SRS, piece.PieceO, piece.NormalState, 4, 10, nil, true, 0, 0, 0},
+		{"ARS T off the left wall", piece.ARS, piece.PieceT, piece.LeftState, 1, 10, nil, true, 1, 1, 0},
+		{"NRS T blocked", piece.NRS, piece.PieceT, piece.NormalState, 4, 10, [][2]block.Position{{4, 11}}, true, piece.NoKick, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rotationSystem := piece.GetRotationSystem(test.rotationSystem)
+			board := CreateBoard()
+
+			for _, square := range test.filled {
+				board.SetSquares([]*block.Block{block.CreateBlock(square[0], square[1], piece.Garbage)})
+			}
+
+			blocks := placePiece(board, rotationSystem, test.pieceType, test.state, test.x, test.y)
+
+			newState, kick := piece.State(0), 0
+			expectedState := (test.state + 1) % piece.NumStates
+
+			if test.right {
+				newState, kick = board.RotateBlocksRight(blocks, test.state, rotationSystem)
+			} else {
+				newState, kick = board.RotateBlocksLeft(blocks, test.state, rotationSystem)
+				expectedState = (test.state + piece.NumStates - 1) % piece.NumStates
+			}
+
+			if kick != test.kick {
+				t.Fatalf("used kick %d, expected %d", kick, test.kick)
+			}
+
+			if kick == piece.NoKick {
+				expectedState = test.state
+			}
+
+			if newState != expectedState {
+				t.Fatalf("ended in state %d, expected %d", newState, expectedState)
+			}
+
+			coords := rotationSystem.Coords(test.pieceType, expectedState)
+
+			for i, pieceBlock := range blocks {
+				x, y := test.x+coords[0][i]+test.kickX, test.y+coords[1][i]+test.kickY
+
+				if pieceBlock.X() != x || pieceBlock.Y() != y {
+					t.Fatalf("block %d is at (%d, %d), expected (%d, %d)", i, pieceBlock.X(), pieceBlock.Y(), x, y)
+				}
+
+				if board.Squares()[y][x] != pieceBlock {
+					t.Fatalf("block %d isn't on the board", i)
+				}
+			}
+		})
+	}
+}
+
+func TestBoardJSON(t *testing.T) {
+	board := CreateBoardWithDimensions(6, 8)
+	board.SetSquares([]*block.Block{block.CreateBlock(1, 0, piece.PieceS), block.CreateBlock(5, 7, piece.Garbage)})
+
+	data, err := board.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &Board{}
+
+	err = restored.UnmarshalJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !restored.Equal(board) || !board.Copy().Equal(board) {
+		t.Fatal("the board changed")
+	}
+}
+
+func TestDestroyFullRows(t *testing.T) {
+	board := CreateBoardWithDimensions(4, 4)
+
+	for _, square := range [][2]block.Position{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}, {3, 2}, {2, 3}} {
+		board.SetSquares([]*block.Block{block.CreateBlock(square[0], square[1], piece.Garbage)})
+	}
+
+	rows := board.DestroyFullRows()
+	if len(rows) != 2 || rows[0] != 0 || rows[1] != 2 {
+		t.Fatalf("destroyed rows %v, expected [0 2]", rows)
+	}
+
+	expected := CreateBoardWithDimensions(4, 4)
+	expected.SetSquares([]*block.Block{block.CreateBlock(0, 0, piece.Garbage), block.CreateBlock(2, 1, piece.Garbage)})
+
+	if !board.Equal(expected) {
+		t.Fatal("the rows above didn't fall into place")
+	}
+}
+
+func TestInsertGarbage(t *testing.T) {
+	tests := []struct {
+		name  string
+		rows  Size
+		stack Size
+		fits  bool
+	}{
+		{"on an empty board", 3, 0, true},
+		{"under a stack", 3, 5, true},
+		{"pushing the stack out", 3, DefaultHeight - 2, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board := CreateBoard()
+
+			for y := 0; y < test.stack; y++ {
+				board.SetSquares([]*block.Block{block.CreateBlock(0, y, piece.PieceI)})
+			}
+
+			fits := board.InsertGarbage(test.rows, 2)
+			if fits != test.fits {
+				t.Fatalf("got fits %t, expected %t", fits, test.fits)
+			}
+
+			for y := 0; y < test.rows; y++ {
+				for x := 0; x < board.Width(); x++ {
+					if (x == 2) != (board.Squares()[y][x] == nil) {
+						t.Fatalf("square (%d, %d) doesn't match the garbage row", x, y)
+					}
+				}
+			}
+
+			if test.fits && test.stack > 0 && board.Squares()[test.rows+test.stack-1][0] == nil {
+				t.Fatal("the stack wasn't pushed up")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package game
+
+import (
+	"time"
+)
+
+// Clock tells the game what time it is
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+// RealClock follows the wall clock
+type RealClock struct{}
+
+// ManualClock only moves when it is told to, so the game can be stepped deterministically
+type ManualClock struct {
+	now time.Time
+}
+
+// Now returns the current wall clock time
+func (clock RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// CreateManualClock creates a new manual clock starting at the given time
+func CreateManualClock(start time.Time) *ManualClock {
+	return &ManualClock{
+		now: start,
+	}
+}
+
+// Now returns the clock's current time
+func (clock *ManualClock) Now() time.Time {
+	return clock.now
+}
+
+// Advance moves the clock forward
+func (clock *ManualClock) Advance(duration time.Duration) {
+	clock.now = clock.now.Add(duration)
+}
+
+// Set moves the clock to the given time
+func (clock *ManualClock) Set(now time.Time) {
+	clock.now = now
+}
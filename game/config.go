@@ -0,0 +1,106 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+)
+
+const (
+	defaultQueueLength = 5
+
+	defaultLockDelay = 500 * time.Millisecond
+
+	defaultLockResets = 15
+
+	defaultStartLevel = 1
+
+	defaultLinesPerLevel = 10
+
+	guidelineLevels = 20
+)
+
+// Config holds the settings used to create a game.
+// Games created with the same seed and settings deal the same sequence of pieces.
+// Everything but the clock can be stored as JSON.
+// RotationSystem names a rotation system registered in the piece package.
+// LockResets limits how many times moving a grounded piece restarts its lock delay.
+// GravityCurve holds the time a piece takes to fall one row at each level, starting at level 1
+// (the last entry is used for any higher level).
+type Config struct {
+	Width          board.Size
+	Height         board.Size
+	QueueLength    int
+	Randomizer     RandomizerType
+	Seed           int64
+	Clock          Clock `json:"-"`
+	RotationSystem string
+	LockDelay      time.Duration
+	LockResets     int
+	StartLevel     int
+	LinesPerLevel  int
+	GravityCurve   []time.Duration
+	Scorer         ScorerType
+}
+
+// DefaultConfig returns the default game settings, seeded from the current time
+func DefaultConfig() Config {
+	return Config{
+		Width:          board.DefaultWidth,
+		Height:         board.DefaultHeight,
+		QueueLength:    defaultQueueLength,
+		Randomizer:     RandomizerPure,
+		Seed:           time.Now().UnixNano(),
+		Clock:          RealClock{},
+		RotationSystem: piece.SRS,
+		LockDelay:      defaultLockDelay,
+		LockResets:     defaultLockResets,
+		StartLevel:     defaultStartLevel,
+		LinesPerLevel:  defaultLinesPerLevel,
+		GravityCurve:   GuidelineGravityCurve(),
+		Scorer:         ScorerGuideline,
+	}
+}
+
+// Validate checks that games can be created with the settings
+// (the queue length can't be negative, and the board must be big enough for every piece to spawn)
+func (config Config) Validate() error {
+	if config.QueueLength < 0 {
+		return fmt.Errorf("the queue length can't be %d", config.QueueLength)
+	}
+
+	rotationSystem := piece.GetRotationSystem(config.RotationSystem)
+	if rotationSystem == nil {
+		rotationSystem = piece.GetRotationSystem(piece.SRS)
+	}
+
+	for _, pieceType := range piece.Types {
+		coords := rotationSystem.Coords(pieceType, rotationSystem.SpawnState(pieceType))
+
+		for i := range coords[0] {
+			x := config.Width/2 + coords[0][i]
+			y := config.Height - 2 + coords[1][i]
+
+			if x < 0 || x >= config.Width || y < 0 || y >= config.Height {
+				return fmt.Errorf("a %dx%d board is too small for the pieces to spawn", config.Width, config.Height)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GuidelineGravityCurve returns the guideline gravity curve, which reaches 20G at level 20
+func GuidelineGravityCurve() []time.Duration {
+	curve := make([]time.Duration, guidelineLevels)
+
+	for level := 1; level <= guidelineLevels; level++ {
+		seconds := math.Pow(0.8-float64(level-1)*0.007, float64(level-1))
+		curve[level-1] = time.Duration(seconds * float64(time.Second))
+	}
+
+	return curve
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+const (
+	// EventPieceSpawned is the type of PieceSpawnedEvent
+	EventPieceSpawned = "PieceSpawned"
+
+	// EventPieceLocked is the type of PieceLockedEvent
+	EventPieceLocked = "PieceLocked"
+
+	// EventLinesCleared is the type of LinesClearedEvent
+	EventLinesCleared = "LinesCleared"
+
+	// EventLevelUp is the type of LevelUpEvent
+	EventLevelUp = "LevelUp"
+
+	// EventPaused is the type of PausedEvent
+	EventPaused = "Paused"
+
+	// EventResumed is the type of ResumedEvent
+	EventResumed = "Resumed"
+
+	// EventGarbageInserted is the type of GarbageInsertedEvent
+	EventGarbageInserted = "GarbageInserted"
+
+	// EventGameOver is the type of GameOverEvent
+	EventGameOver = "GameOver"
+)
+
+// EventType identifies the kind of an event
+type EventType = string
+
+// Event is something that happened in a game
+type Event interface {
+	// Type returns the kind of the event
+	Type() EventType
+}
+
+// Listener is called with the events a game publishes
+type Listener = func(event Event)
+
+// PieceSpawnedEvent is published when a new piece appears at the top of the board
+type PieceSpawnedEvent struct {
+	PieceType block.Type
+}
+
+// PieceLockedEvent is published when a piece locks, with the result of the lock
+type PieceLockedEvent struct {
+	Result ClearResult
+}
+
+// LinesClearedEvent is published when a lock clears lines
+type LinesClearedEvent struct {
+	Rows []int
+}
+
+// LevelUpEvent is published when the level rises
+type LevelUpEvent struct {
+	Level int
+}
+
+// PausedEvent is published when the game is paused
+type PausedEvent struct{}
+
+// ResumedEvent is published when the game is resumed
+type ResumedEvent struct{}
+
+// GarbageInsertedEvent is published when queued garbage rows are pushed into the bottom of the board
+type GarbageInsertedEvent struct {
+	Lines int
+	Hole  block.Position
+}
+
+// GameOverEvent is published when the game ends, either because the stack topped out or because it was closed
+type GameOverEvent struct {
+	Score     int
+	ToppedOut bool
+}
+
+// subscription holds a listener and the id used to unsubscribe it
+type subscription struct {
+	id       int
+	listener Listener
+}
+
+// Type returns the kind of the event
+func (event PieceSpawnedEvent) Type() EventType {
+	return EventPieceSpawned
+}
+
+// Type returns the kind of the event
+func (event PieceLockedEvent) Type() EventType {
+	return EventPieceLocked
+}
+
+// Type returns the kind of the event
+func (event LinesClearedEvent) Type() EventType {
+	return EventLinesCleared
+}
+
+// Type returns the kind of the event
+func (event LevelUpEvent) Type() EventType {
+	return EventLevelUp
+}
+
+// Type returns the kind of the event
+func (event PausedEvent) Type() EventType {
+	return EventPaused
+}
+
+// Type returns the kind of the event
+func (event ResumedEvent) Type() EventType {
+	return EventResumed
+}
+
+// Type returns the kind of the event
+func (event GarbageInsertedEvent) Type() EventType {
+	return EventGarbageInserted
+}
+
+// Type returns the kind of the event
+func (event GameOverEvent) Type() EventType {
+	return EventGameOver
+}
+
+// Subscribe registers a listener for the game's events and returns the id used to unsubscribe it.
+// Listeners are called synchronously from Update, in the order they subscribed.
+func (game *Game) Subscribe(listener Listener) int {
+	game.nextSubscription++
+
+	game.subscriptions = append(game.subscriptions, subscription{
+		id:       game.nextSubscription,
+		listener: listener,
+	})
+
+	return game.nextSubscription
+}
+
+// Unsubscribe removes a listener
+func (game *Game) Unsubscribe(id int) {
+	for i, subscription := range game.subscriptions {
+		if subscription.id == id {
+			game.subscriptions = append(game.subscriptions[:i:i], game.subscriptions[i+1:]...)
+			return
+		}
+	}
+}
+
+// publish sends an event to every listener
+func (game *Game) publish(event Event) {
+	for _, subscription := range game.subscriptions {
+		subscription.listener(event)
+	}
+}
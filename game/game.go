@@ -25,6 +25,15 @@ const (
 	// RotateRight represents a rightwards rotation move
 	RotateRight = "RotateRight"
 
+	// HardDrop represents a move that drops the current piece as far as possible and locks it
+	HardDrop = "HardDrop"
+
+	// SoftDrop represents a move that speeds up gravity while it is held
+	SoftDrop = "SoftDrop"
+
+	// Hold represents a move that swaps the current piece with the held piece
+	Hold = "Hold"
+
 	// NoMove represents no move
 	NoMove = ""
 
@@ -33,9 +42,13 @@ const (
 	// Closed is a flag used to tell the game to finish (because the window was closed)
 	Closed = "Closed"
 
-	oneSecond = 1
+	softDropFactor = 20
+
+	softDropScore = 1
 
-	scoreMultiplier = 20
+	hardDropScore = 2
+
+	tSpinKick = 4
 )
 
 // Move type
@@ -50,6 +63,36 @@ type Game struct {
 	score             int
 	paused            bool
 	timeSinceLastMove time.Duration
+	heldPiece         block.Type
+	canHold           bool
+	queue             []block.Type
+	randomizer        Randomizer
+	seed              int64
+	clock             Clock
+	lastKick          int
+	rotationSystem    piece.RotationSystem
+	lockDelay         time.Duration
+	lockResetLimit    int
+	locking           bool
+	lockTime          time.Time
+	timeSinceLock     time.Duration
+	lockResets        int
+	lowestRow         block.Position
+	level             int
+	lines             int
+	startLevel        int
+	linesPerLevel     int
+	gravityCurve      []time.Duration
+	scorer            Scorer
+	combo             int
+	backToBack        bool
+	lastClear         ClearResult
+	subscriptions     []subscription
+	nextSubscription  int
+	config            Config
+	startTime         time.Time
+	dealt             int
+	garbage           []Garbage
 }
 
 // IsRunning checks if game is running
@@ -67,6 +110,69 @@ func (game *Game) Score() int {
 	return game.score
 }
 
+// CurrentPiece returns the piece currently falling (nil once the game is over)
+func (game *Game) CurrentPiece() *piece.Piece {
+	return game.currentPiece
+}
+
+// Level returns the game's current level
+func (game *Game) Level() int {
+	return game.level
+}
+
+// Lines returns the number of lines cleared so far
+func (game *Game) Lines() int {
+	return game.lines
+}
+
+// LastClear returns the result of the last locked piece
+func (game *Game) LastClear() ClearResult {
+	return game.lastClear
+}
+
+// Combo returns the number of consecutive pieces that cleared lines, minus one (-1 if the last piece cleared none)
+func (game *Game) Combo() int {
+	return game.combo
+}
+
+// BackToBack checks if the next difficult clear (a tetris or a T-spin) is back-to-back
+func (game *Game) BackToBack() bool {
+	return game.backToBack
+}
+
+// HeldPiece returns the type of the held piece (piece.NoPiece if there is none)
+func (game *Game) HeldPiece() block.Type {
+	return game.heldPiece
+}
+
+// Queue returns the types of the upcoming pieces, next piece first
+func (game *Game) Queue() []block.Type {
+	queue := make([]block.Type, len(game.queue))
+	copy(queue, game.queue)
+
+	return queue
+}
+
+// RotationSystem returns the rotation system used by the game
+func (game *Game) RotationSystem() piece.RotationSystem {
+	return game.rotationSystem
+}
+
+// Elapsed returns the time since the game started
+func (game *Game) Elapsed() time.Duration {
+	return game.clock.Now().Sub(game.startTime)
+}
+
+// Config returns the settings the game was created with
+func (game *Game) Config() Config {
+	return game.config
+}
+
+// Seed returns the seed used to generate the game's pieces
+func (game *Game) Seed() int64 {
+	return game.seed
+}
+
 // IsPaused checks if the game is paused.
 func (game *Game) IsPaused() bool {
 	return game.paused
@@ -74,73 +180,149 @@ func (game *Game) IsPaused() bool {
 
 // CreateGame creates a new game
 func CreateGame() *Game {
-	rand.Seed(time.Now().UnixNano())
+	return CreateGameWithConfig(DefaultConfig())
+}
 
-	board := board.CreateBoard()
+// CreateGameWithDimensions creates a new game using custom dimensions for the board
+func CreateGameWithDimensions(width, height board.Size) *Game {
+	config := DefaultConfig()
+	config.Width = width
+	config.Height = height
+
+	return CreateGameWithConfig(config)
+}
 
-	currentPiece := generateNewPiece(board)
+// CreateGameWithConfig creates a new game using custom settings
+func CreateGameWithConfig(config Config) *Game {
+	game := createGame(config)
 
-	return &Game{
-		running:      true,
-		board:        board,
-		currentPiece: currentPiece,
-		lastTime:     time.Now(),
+	for i := 0; i < config.QueueLength; i++ {
+		game.queue = append(game.queue, game.dealPiece())
 	}
+
+	game.currentPiece = game.generateNewPiece()
+
+	return game
 }
 
-// CreateGameWithDimensions creates a new game using custom dimensions for the board
-func CreateGameWithDimensions(width, height board.Size) *Game {
-	rand.Seed(time.Now().UnixNano())
+// createGame creates a game with an empty board, no current piece and an empty queue
+func createGame(config Config) *Game {
+	random := rand.New(rand.NewSource(config.Seed))
 
-	board := board.CreateBoardWithDimensions(width, height)
+	clock := config.Clock
+	if clock == nil {
+		clock = RealClock{}
+	}
 
-	currentPiece := generateNewPiece(board)
+	rotationSystem := piece.GetRotationSystem(config.RotationSystem)
+	if rotationSystem == nil {
+		rotationSystem = piece.GetRotationSystem(piece.SRS)
+	}
 
 	return &Game{
-		running:      true,
-		board:        board,
-		currentPiece: currentPiece,
-		lastTime:     time.Now(),
+		running:        true,
+		board:          board.CreateBoardWithDimensions(config.Width, config.Height),
+		lastTime:       clock.Now(),
+		heldPiece:      piece.NoPiece,
+		canHold:        true,
+		queue:          make([]block.Type, 0, config.QueueLength),
+		randomizer:     CreateRandomizer(config.Randomizer, random),
+		seed:           config.Seed,
+		clock:          clock,
+		rotationSystem: rotationSystem,
+		lockDelay:      config.LockDelay,
+		lockResetLimit: config.LockResets,
+		level:          config.StartLevel,
+		startLevel:     config.StartLevel,
+		linesPerLevel:  config.LinesPerLevel,
+		gravityCurve:   config.GravityCurve,
+		scorer:         CreateScorer(config.Scorer),
+		combo:          -1,
+		config:         config,
+		startTime:      clock.Now(),
 	}
 }
 
-func generateNewPiece(board *board.Board) *piece.Piece {
-	random := rand.Intn(piece.NumPieces)
+// Copy copies the game, so moves can be tried on the copy without changing the game.
+// The copy shares the clock, deals the same pieces and has no subscribers
+func (game *Game) Copy() *Game {
+	copied := *game
 
-	var pieceType block.Type
-	var pieceCoords [][]block.Position
+	config := game.config
+	config.Clock = game.clock
 
-	switch random {
-	case 0:
-		pieceType = piece.PieceI
-		pieceCoords = piece.PieceICoords[0]
-		break
-	case 1:
-		pieceType = piece.PieceJ
-		pieceCoords = piece.PieceJCoords[0]
-		break
-	case 2:
-		pieceType = piece.PieceL
-		pieceCoords = piece.PieceLCoords[0]
-		break
-	case 3:
-		pieceType = piece.PieceO
-		pieceCoords = piece.PieceOCoords[0]
-		break
-	case 4:
-		pieceType = piece.PieceS
-		pieceCoords = piece.PieceSCoords[0]
-		break
-	case 5:
-		pieceType = piece.PieceT
-		pieceCoords = piece.PieceTCoords[0]
-		break
-	case 6:
-		pieceType = piece.PieceZ
-		pieceCoords = piece.PieceZCoords[0]
-		break
+	dealer := createGame(config)
+
+	for dealer.dealt < game.dealt {
+		dealer.dealPiece()
+	}
+
+	copied.randomizer = dealer.randomizer
+	copied.board = game.board.Copy()
+	copied.queue = append(make([]block.Type, 0, cap(game.queue)), game.queue...)
+	copied.garbage = append([]Garbage(nil), game.garbage...)
+	copied.subscriptions = nil
+	copied.nextSubscription = 0
+
+	if game.currentPiece != nil {
+		blocks := make([]*block.Block, 0, len(game.currentPiece.Blocks()))
+
+		for _, pieceBlock := range game.currentPiece.Blocks() {
+			blocks = append(blocks, copied.board.Squares()[pieceBlock.Y()][pieceBlock.X()])
+		}
+
+		copied.currentPiece = piece.CreatePiece(blocks)
+		copied.currentPiece.SetState(game.currentPiece.State())
 	}
 
+	return &copied
+}
+
+// dealPiece takes a piece type from the randomizer, counting how many were dealt
+func (game *Game) dealPiece() block.Type {
+	game.dealt++
+
+	return game.randomizer.Next()
+}
+
+// nextPieceType takes the next piece type from the queue and refills it
+func (game *Game) nextPieceType() block.Type {
+	pieceType := game.dealPiece()
+
+	if len(game.queue) > 0 {
+		game.queue = append(game.queue, pieceType)
+		pieceType = game.queue[0]
+		game.queue = game.queue[1:]
+	}
+
+	return pieceType
+}
+
+// generateNewPiece spawns the next piece on the board
+func (game *Game) generateNewPiece() *piece.Piece {
+	return game.spawnPiece(game.nextPieceType())
+}
+
+// spawnPiece spawns a piece of the given type and resets the per-piece state
+func (game *Game) spawnPiece(pieceType block.Type) *piece.Piece {
+	game.lastKick = piece.NoKick
+	game.locking = false
+	game.lockResets = 0
+
+	newPiece := spawnPiece(game.board, pieceType, game.rotationSystem)
+	if newPiece != nil {
+		game.lowestRow = bottomRow(newPiece)
+		game.publish(PieceSpawnedEvent{PieceType: pieceType})
+	}
+
+	return newPiece
+}
+
+// spawnPiece places a new piece of the given type at the top of the board
+func spawnPiece(board *board.Board, pieceType block.Type, rotationSystem piece.RotationSystem) *piece.Piece {
+	state := rotationSystem.SpawnState(pieceType)
+	pieceCoords := rotationSystem.Coords(pieceType, state)
+
 	blocks := make([]*block.Block, 4)
 
 	for i := 0; i < 4; i++ {
@@ -155,6 +337,7 @@ func generateNewPiece(board *board.Board) *piece.Piece {
 	}
 
 	piece := piece.CreatePiece(blocks)
+	piece.SetState(state)
 
 	board.SetSquares(piece.Blocks())
 
@@ -163,27 +346,35 @@ func generateNewPiece(board *board.Board) *piece.Piece {
 
 // Update updates the game
 func (game *Game) Update(move Move) {
+	if !game.running {
+		return
+	}
+
+	if move == Closed {
+		game.endGame(false)
+		return
+	}
+
 	if move == Paused {
 		game.paused = !game.paused
 
 		if game.paused {
-			game.timeSinceLastMove = time.Now().Sub(game.lastTime)
+			game.timeSinceLastMove = game.clock.Now().Sub(game.lastTime)
+			game.timeSinceLock = game.clock.Now().Sub(game.lockTime)
+			game.publish(PausedEvent{})
 		} else {
-			game.lastTime = time.Now().Add(-1 * game.timeSinceLastMove)
+			game.lastTime = game.clock.Now().Add(-1 * game.timeSinceLastMove)
+			game.lockTime = game.clock.Now().Add(-1 * game.timeSinceLock)
+			game.publish(ResumedEvent{})
 		}
 	}
 
 	if !game.paused {
 		if move != NoMove {
-			if move == Closed {
-				game.running = false
-				return
-			}
-
 			game.makeMove(move)
 		}
 
-		game.fallCurrentPiece()
+		game.fallCurrentPiece(move == SoftDrop)
 	}
 }
 
@@ -194,8 +385,7 @@ func (game *Game) makeMove(move Move) bool {
 	case MoveDown:
 		res = game.movePieceDown()
 		if !res {
-			game.lastTime = time.Now()
-			game.executeFallCurrentPiece()
+			game.startLock()
 		}
 		break
 	case MoveRight:
@@ -210,6 +400,21 @@ func (game *Game) makeMove(move Move) bool {
 	case RotateRight:
 		res = game.rotatePieceRight()
 		break
+	case HardDrop:
+		res = game.hardDropPiece()
+		break
+	case Hold:
+		res = game.holdPiece()
+		break
+	}
+
+	if res && move != HardDrop && move != Hold {
+		game.resetLock()
+
+		// a piece moved or rotated onto the stack starts locking without waiting for gravity
+		if game.board.DropDistance(game.currentPiece.Blocks()) == 0 {
+			game.startLock()
+		}
 	}
 
 	return res
@@ -219,21 +424,41 @@ func (game *Game) makeMove(move Move) bool {
 func (game *Game) movePieceDown() bool {
 	blocks := game.currentPiece.Blocks()
 
-	return game.board.MoveBlocksDown(blocks)
+	res := game.board.MoveBlocksDown(blocks)
+	if res {
+		game.lastKick = piece.NoKick
+
+		if bottomRow(game.currentPiece) < game.lowestRow {
+			game.lowestRow = bottomRow(game.currentPiece)
+			game.lockResets = 0
+		}
+	}
+
+	return res
 }
 
 // movePieceRight moves current piece right
 func (game *Game) movePieceRight() bool {
 	blocks := game.currentPiece.Blocks()
 
-	return game.board.MoveBlocksRight(blocks)
+	res := game.board.MoveBlocksRight(blocks)
+	if res {
+		game.lastKick = piece.NoKick
+	}
+
+	return res
 }
 
 // movePieceLeft moves current piece left
 func (game *Game) movePieceLeft() bool {
 	blocks := game.currentPiece.Blocks()
 
-	return game.board.MoveBlocksLeft(blocks)
+	res := game.board.MoveBlocksLeft(blocks)
+	if res {
+		game.lastKick = piece.NoKick
+	}
+
+	return res
 }
 
 // rotatePieceRight moves current piece right
@@ -241,11 +466,15 @@ func (game *Game) rotatePieceRight() bool {
 	blocks := game.currentPiece.Blocks()
 
 	oldState := game.currentPiece.State()
-	newState := game.board.RotateBlocksRight(blocks, oldState)
+	newState, kick := game.board.RotateBlocksRight(blocks, oldState, game.rotationSystem)
 
 	game.currentPiece.SetState(newState)
 
-	return newState != oldState
+	if kick != piece.NoKick {
+		game.lastKick = kick
+	}
+
+	return kick != piece.NoKick
 }
 
 // rotatePieceLeft moves current piece left
@@ -253,37 +482,276 @@ func (game *Game) rotatePieceLeft() bool {
 	blocks := game.currentPiece.Blocks()
 
 	oldState := game.currentPiece.State()
-	newState := game.board.RotateBlocksLeft(blocks, oldState)
+	newState, kick := game.board.RotateBlocksLeft(blocks, oldState, game.rotationSystem)
 
 	game.currentPiece.SetState(newState)
 
-	return newState != oldState
+	if kick != piece.NoKick {
+		game.lastKick = kick
+	}
+
+	return kick != piece.NoKick
 }
 
-// fallCurrentPiece moves current piece down if possible
-func (game *Game) fallCurrentPiece() {
-	now := time.Now()
+// hardDropPiece drops the current piece as far as possible and locks it
+func (game *Game) hardDropPiece() bool {
+	for game.movePieceDown() {
+		game.score += hardDropScore
+	}
+
+	game.lastTime = game.clock.Now()
+	game.executeFallCurrentPiece()
+
+	return true
+}
+
+// holdPiece swaps the current piece with the held piece (once per spawn)
+func (game *Game) holdPiece() bool {
+	if !game.canHold {
+		return false
+	}
+
+	pieceType := game.currentPiece.Type()
+
+	game.board.ClearSquares(game.currentPiece.Blocks())
+
+	if game.heldPiece == piece.NoPiece {
+		game.currentPiece = game.generateNewPiece()
+	} else {
+		game.currentPiece = game.spawnPiece(game.heldPiece)
+	}
+
+	game.heldPiece = pieceType
+	game.canHold = false
+	game.lastTime = game.clock.Now()
+
+	if game.currentPiece == nil {
+		game.endGame(true)
+	}
+
+	return true
+}
+
+// startLock starts the lock delay of the current piece if it is not running yet
+func (game *Game) startLock() {
+	if !game.locking {
+		game.locking = true
+		game.lockTime = game.clock.Now()
+	}
+}
+
+// resetLock restarts the lock delay after a successful move, up to the reset limit
+func (game *Game) resetLock() {
+	if !game.locking || game.lockResets >= game.lockResetLimit {
+		return
+	}
 
-	if now.Sub(game.lastTime).Seconds() > oneSecond {
+	game.lockResets++
+	game.lockTime = game.clock.Now()
+
+	if game.board.DropDistance(game.currentPiece.Blocks()) > 0 {
+		game.locking = false
+	}
+}
+
+// bottomRow returns the lowest row occupied by a piece
+func bottomRow(piece *piece.Piece) block.Position {
+	row := piece.Blocks()[0].Y()
+
+	for _, block := range piece.Blocks() {
+		if block.Y() < row {
+			row = block.Y()
+		}
+	}
+
+	return row
+}
+
+// fallCurrentPiece moves current piece down if possible (faster while soft dropping)
+// and locks it once the lock delay runs out
+func (game *Game) fallCurrentPiece(softDrop bool) {
+	now := game.clock.Now()
+
+	interval := game.gravityInterval()
+	if softDrop {
+		interval /= softDropFactor
+	}
+
+	elapsed := now.Sub(game.lastTime)
+
+	if elapsed > interval {
 		game.lastTime = now
-		res := game.movePieceDown()
 
-		if !res {
+		rows := game.board.Height()
+		if interval > 0 && elapsed/interval < time.Duration(rows) {
+			rows = int(elapsed / interval)
+		}
+
+		for row := 0; row < rows; row++ {
+			if !game.movePieceDown() {
+				game.startLock()
+				break
+			}
+
+			if softDrop {
+				game.score += softDropScore
+			}
+		}
+	}
+
+	if game.locking && now.Sub(game.lockTime) >= game.lockDelay {
+		game.locking = false
+
+		if game.board.DropDistance(game.currentPiece.Blocks()) == 0 {
+			game.lastTime = now
 			game.executeFallCurrentPiece()
 		}
 	}
 }
 
-// executeFallCurrentPiece moves current piece down
+// detectTSpin checks if the current piece is a T-spin using the 3-corner rule.
+// The piece must have been rotated last, and a T-spin mini is upgraded when the last kick was used.
+func (game *Game) detectTSpin() TSpinType {
+	if game.currentPiece.Type() != piece.PieceT || game.lastKick == piece.NoKick {
+		return TSpinNone
+	}
+
+	centerX, centerY, frontX, frontY := findTCenterAndFront(game.currentPiece.Blocks())
+
+	corners := 0
+	for _, dx := range []block.Position{-1, 1} {
+		for _, dy := range []block.Position{-1, 1} {
+			if game.board.IsOccupied(centerX+dx, centerY+dy) {
+				corners++
+			}
+		}
+	}
+
+	if corners < 3 {
+		return TSpinNone
+	}
+
+	// the front corners are on the side the T is pointing to
+	frontCorner1 := game.board.IsOccupied(centerX+frontX+frontY, centerY+frontY+frontX)
+	frontCorner2 := game.board.IsOccupied(centerX+frontX-frontY, centerY+frontY-frontX)
+
+	if (frontCorner1 && frontCorner2) || game.lastKick == tSpinKick {
+		return TSpinFull
+	}
+
+	return TSpinMini
+}
+
+// findTCenterAndFront finds the center block of a T piece and the direction it is pointing to
+func findTCenterAndFront(blocks []*block.Block) (block.Position, block.Position, block.Position, block.Position) {
+	occupied := func(x, y block.Position) bool {
+		for _, block := range blocks {
+			if block.X() == x && block.Y() == y {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, center := range blocks {
+		neighbours := 0
+		frontX, frontY := 0, 0
+
+		for _, direction := range [][2]block.Position{{0, 1}, {1, 0}, {0, -1}, {-1, 0}} {
+			if occupied(center.X()+direction[0], center.Y()+direction[1]) {
+				neighbours++
+
+				if !occupied(center.X()-direction[0], center.Y()-direction[1]) {
+					frontX, frontY = direction[0], direction[1]
+				}
+			}
+		}
+
+		if neighbours == 3 {
+			return center.X(), center.Y(), frontX, frontY
+		}
+	}
+
+	return blocks[0].X(), blocks[0].Y(), 0, 0
+}
+
+// gravityInterval returns the time the current piece takes to fall one row at the current level
+func (game *Game) gravityInterval() time.Duration {
+	if len(game.gravityCurve) == 0 {
+		return time.Second
+	}
+
+	index := game.level - 1
+	if index < 0 {
+		index = 0
+	} else if index >= len(game.gravityCurve) {
+		index = len(game.gravityCurve) - 1
+	}
+
+	return game.gravityCurve[index]
+}
+
+// executeFallCurrentPiece locks the current piece, scores it and spawns the next one
 func (game *Game) executeFallCurrentPiece() {
-	numRowsDestroyed := game.Board().DestroyFullRows()
-	if numRowsDestroyed > 0 {
-		game.score += numRowsDestroyed * scoreMultiplier
+	result := ClearResult{
+		PieceType: game.currentPiece.Type(),
+		TSpin:     game.detectTSpin(),
+		Level:     game.level,
+	}
+
+	result.Rows = game.Board().DestroyFullRows()
+	result.Lines = len(result.Rows)
+
+	if result.Lines > 0 {
+		game.combo++
+		result.Combo = game.combo
+		result.BackToBack = game.backToBack && result.Difficult()
+		result.PerfectClear = game.board.IsEmpty()
+		game.backToBack = result.Difficult()
+	} else {
+		game.combo = -1
 	}
 
-	game.currentPiece = generateNewPiece(game.board)
+	result.Points = game.scorer.Score(result)
+	game.score += result.Points
+
+	// the lock's attack cancels queued garbage first, so every copy of the game (replicas included) cancels the same rows
+	result.Attack = game.CancelGarbage(Attack(result))
+	game.lastClear = result
+
+	game.publish(PieceLockedEvent{Result: result})
+
+	if result.Lines > 0 {
+		game.lines += result.Lines
+		game.publish(LinesClearedEvent{Rows: result.Rows})
+
+		if game.linesPerLevel > 0 && game.startLevel+game.lines/game.linesPerLevel > game.level {
+			game.level = game.startLevel + game.lines/game.linesPerLevel
+			game.publish(LevelUpEvent{Level: game.level})
+		}
+	}
+
+	if result.Lines == 0 && !game.insertGarbage() {
+		game.currentPiece = nil
+		game.endGame(true)
+		return
+	}
+
+	game.currentPiece = game.generateNewPiece()
+	game.canHold = true
 
 	if game.currentPiece == nil {
-		game.running = false
+		game.endGame(true)
 	}
 }
+
+// endGame stops the game
+func (game *Game) endGame(toppedOut bool) {
+	game.running = false
+
+	game.publish(GameOverEvent{
+		Score:     game.score,
+		ToppedOut: toppedOut,
+	})
+}
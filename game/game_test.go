@@ -0,0 +1,272 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// createTestGame creates a game with the default settings and a clock that only moves when told to
+func createTestGame(seed int64) (*Game, *ManualClock) {
+	clock := CreateManualClock(time.Unix(0, 0))
+
+	config := DefaultConfig()
+	config.Seed = seed
+	config.Clock = clock
+
+	return CreateGameWithConfig(config), clock
+}
+
+// replacePiece swaps the current piece for a new piece of some type
+func replacePiece(game *Game, pieceType block.Type) {
+	game.board.ClearSquares(game.currentPiece.Blocks())
+	game.currentPiece = game.spawnPiece(pieceType)
+}
+
+// fillRow fills a row of the board with garbage, except for some holes
+func fillRow(game *Game, y block.Position, holes ...block.Position) {
+	for x := 0; x < game.board.Width(); x++ {
+		hole := false
+
+		for _, holeX := range holes {
+			hole = hole || holeX == x
+		}
+
+		if !hole {
+			game.board.SetSquares([]*block.Block{block.CreateBlock(x, y, piece.Garbage)})
+		}
+	}
+}
+
+// land moves the current piece down until it rests on the floor
+func land(game *Game) {
+	for game.board.DropDistance(game.currentPiece.Blocks()) > 0 {
+		game.Update(MoveDown)
+	}
+
+	game.Update(MoveDown)
+}
+
+func TestLockDelay(t *testing.T) {
+	const step = 100 * time.Millisecond
+
+	tests := []struct {
+		name  string
+		moves []Move
+		locks int
+	}{
+		{"locks once the delay runs out", nil, int(defaultLockDelay / step)},
+		{"a move restarts the delay", []Move{NoMove, NoMove, NoMove, MoveLeft}, 4 + int(defaultLockDelay/step)},
+		{"a failed move doesn't restart the delay", []Move{NoMove, NoMove, NoMove, MoveDown}, int(defaultLockDelay / step)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, clock := createTestGame(1)
+			replacePiece(game, piece.PieceO)
+			land(game)
+
+			current := game.CurrentPiece()
+
+			for i := 1; i <= 20; i++ {
+				clock.Advance(step)
+
+				move := NoMove
+				if i-1 < len(test.moves) {
+					move = test.moves[i-1]
+				}
+
+				game.Update(move)
+
+				if game.CurrentPiece() != current {
+					if i != test.locks {
+						t.Fatalf("locked after %d steps, expected %d", i, test.locks)
+					}
+
+					return
+				}
+			}
+
+			t.Fatal("the piece never locked")
+		})
+	}
+}
+
+func TestLockResetsRunOut(t *testing.T) {
+	game, clock := createTestGame(1)
+	replacePiece(game, piece.PieceO)
+	land(game)
+
+	current := game.CurrentPiece()
+	moves := []Move{MoveLeft, MoveRight}
+
+	for i := 0; i < defaultLockResets; i++ {
+		clock.Advance(defaultLockDelay - time.Millisecond)
+		game.Update(moves[i%len(moves)])
+
+		if game.CurrentPiece() != current {
+			t.Fatalf("locked after %d resets", i)
+		}
+	}
+
+	clock.Advance(defaultLockDelay - time.Millisecond)
+	game.Update(MoveLeft)
+	clock.Advance(time.Millisecond)
+	game.Update(NoMove)
+
+	if game.CurrentPiece() == current {
+		t.Fatal("moves kept restarting the delay after the resets ran out")
+	}
+}
+
+func TestDetectTSpin(t *testing.T) {
+	corners := [][2]block.Position{{3, 0}, {5, 0}, {3, 2}}
+
+	tests := []struct {
+		name      string
+		pieceType block.Type
+		state     piece.State
+		corners   [][2]block.Position
+		kick      int
+		tSpin     TSpinType
+	}{
+		{"pointing at two filled corners", piece.PieceT, piece.InvertedState, corners, 0, TSpinFull},
+		{"pointing at one filled corner", piece.PieceT, piece.NormalState, corners, 0, TSpinMini},
+		{"mini upgraded by the last kick", piece.PieceT, piece.NormalState, corners, tSpinKick, TSpinFull},
+		{"moved after rotating", piece.PieceT, piece.InvertedState, corners, piece.NoKick, TSpinNone},
+		{"only two filled corners", piece.PieceT, piece.InvertedState, corners[:2], 0, TSpinNone},
+		{"not a T", piece.PieceJ, piece.InvertedState, corners, 0, TSpinNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, _ := createTestGame(1)
+			game.board = board.CreateBoard()
+
+			for _, corner := range test.corners {
+				game.board.SetSquares([]*block.Block{block.CreateBlock(corner[0], corner[1], piece.Garbage)})
+			}
+
+			coords := game.rotationSystem.Coords(test.pieceType, test.state)
+			blocks := make([]*block.Block, len(coords[0]))
+
+			for i := range blocks {
+				blocks[i] = block.CreateBlock(4+coords[0][i], 1+coords[1][i], test.pieceType)
+			}
+
+			game.currentPiece = piece.CreatePiece(blocks)
+			game.currentPiece.SetState(test.state)
+			game.lastKick = test.kick
+
+			tSpin := game.detectTSpin()
+			if tSpin != test.tSpin {
+				t.Fatalf("got T-spin %q, expected %q", tSpin, test.tSpin)
+			}
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		width       board.Size
+		height      board.Size
+		queueLength int
+		valid       bool
+	}{
+		{board.DefaultWidth, board.DefaultHeight, defaultQueueLength, true},
+		{4, 3, defaultQueueLength, true},
+		{3, board.DefaultHeight, defaultQueueLength, false},
+		{board.DefaultWidth, 2, defaultQueueLength, false},
+		{0, 0, defaultQueueLength, false},
+		{board.DefaultWidth, board.DefaultHeight, 0, true},
+		{board.DefaultWidth, board.DefaultHeight, -1, false},
+	}
+
+	for _, test := range tests {
+		config := DefaultConfig()
+		config.Width = test.width
+		config.Height = test.height
+		config.QueueLength = test.queueLength
+
+		err := config.Validate()
+		if (err == nil) != test.valid {
+			t.Fatalf("%dx%d, queue of %d: got error %v, expected valid to be %t", test.width, test.height, test.queueLength, err, test.valid)
+		}
+
+		if test.valid && CreateGameWithConfig(config).CurrentPiece() == nil {
+			t.Fatalf("%dx%d, queue of %d: no piece spawned with valid settings", test.width, test.height, test.queueLength)
+		}
+	}
+}
+
+func TestGroundingMoveStartsTheLock(t *testing.T) {
+	const step = 100 * time.Millisecond
+
+	game, clock := createTestGame(1)
+	replacePiece(game, piece.PieceO)
+
+	for bottomRow(game.currentPiece) > 2 {
+		game.Update(MoveDown)
+	}
+
+	// a ledge under the square left of the piece, so moving left grounds it
+	left := game.currentPiece.Blocks()[0].X()
+	for _, pieceBlock := range game.currentPiece.Blocks() {
+		if pieceBlock.X() < left {
+			left = pieceBlock.X()
+		}
+	}
+
+	game.board.SetSquares([]*block.Block{block.CreateBlock(left-1, 1, piece.Garbage)})
+	game.Update(MoveLeft)
+
+	current := game.CurrentPiece()
+
+	for i := 1; i <= 20; i++ {
+		clock.Advance(step)
+		game.Update(NoMove)
+
+		if game.CurrentPiece() != current {
+			if i != int(defaultLockDelay/step) {
+				t.Fatalf("locked after %d steps, expected %d", i, int(defaultLockDelay/step))
+			}
+
+			return
+		}
+	}
+
+	t.Fatal("the piece never locked")
+}
+
+func TestCopy(t *testing.T) {
+	original, _ := createTestGame(4)
+	original.Update(MoveLeft)
+
+	board := original.Board().Copy()
+	copied := original.Copy()
+
+	// the copy plays on alone, so the original must not change
+	for i := 0; i < 6; i++ {
+		copied.Update(HardDrop)
+	}
+	copied.Update(Hold)
+
+	if !original.Board().Equal(board) || original.Score() != 0 || original.HeldPiece() != piece.NoPiece {
+		t.Fatal("playing the copy changed the original")
+	}
+
+	// both deal the same pieces from where the copy was made
+	copied = original.Copy()
+
+	for i := 0; i < 6; i++ {
+		original.Update(HardDrop)
+		copied.Update(HardDrop)
+
+		if !copied.Board().Equal(original.Board()) || copied.CurrentPiece().Type() != original.CurrentPiece().Type() {
+			t.Fatalf("the copy diverged after %d pieces", i+1)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package game
+
+import (
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// Garbage is a chunk of garbage rows sent by an opponent, all with their hole in the same column
+type Garbage struct {
+	Lines int            `json:"lines"`
+	Hole  block.Position `json:"hole"`
+}
+
+// QueueGarbage adds garbage to the queue of rows waiting to be inserted.
+// Queued garbage is inserted when a piece locks without clearing lines.
+func (game *Game) QueueGarbage(garbage Garbage) {
+	if garbage.Lines <= 0 {
+		return
+	}
+
+	game.garbage = append(game.garbage, garbage)
+}
+
+// CancelGarbage removes up to some lines from the garbage queue (oldest first), returning the lines left to cancel
+func (game *Game) CancelGarbage(lines int) int {
+	for lines > 0 && len(game.garbage) > 0 {
+		if game.garbage[0].Lines > lines {
+			game.garbage[0].Lines -= lines
+			return 0
+		}
+
+		lines -= game.garbage[0].Lines
+		game.garbage = game.garbage[1:]
+	}
+
+	return lines
+}
+
+// PendingGarbage returns the number of garbage rows waiting to be inserted
+func (game *Game) PendingGarbage() int {
+	lines := 0
+
+	for _, garbage := range game.garbage {
+		lines += garbage.Lines
+	}
+
+	return lines
+}
+
+// insertGarbage inserts every queued garbage row, returning false if the stack was pushed out of the board
+func (game *Game) insertGarbage() bool {
+	fits := true
+
+	for _, garbage := range game.garbage {
+		fits = game.board.InsertGarbage(garbage.Lines, garbage.Hole) && fits
+		game.publish(GarbageInsertedEvent{Lines: garbage.Lines, Hole: garbage.Hole})
+	}
+
+	game.garbage = nil
+
+	return fits
+}
@@ -0,0 +1,99 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/daplf/go-tetris/game/piece"
+)
+
+func TestCancelGarbage(t *testing.T) {
+	tests := []struct {
+		cancel  int
+		left    int
+		pending int
+	}{
+		{0, 0, 5},
+		{2, 0, 3},
+		{3, 0, 2},
+		{5, 0, 0},
+		{7, 2, 0},
+	}
+
+	for _, test := range tests {
+		game, _ := createTestGame(1)
+		game.QueueGarbage(Garbage{Lines: 3, Hole: 1})
+		game.QueueGarbage(Garbage{Lines: 0, Hole: 4})
+		game.QueueGarbage(Garbage{Lines: 2, Hole: 2})
+
+		left := game.CancelGarbage(test.cancel)
+
+		if left != test.left || game.PendingGarbage() != test.pending {
+			t.Fatalf("cancelling %d: got %d left and %d pending, expected %d and %d",
+				test.cancel, left, game.PendingGarbage(), test.left, test.pending)
+		}
+	}
+}
+
+func TestLockInsertsGarbage(t *testing.T) {
+	game, _ := createTestGame(1)
+	game.QueueGarbage(Garbage{Lines: 2, Hole: 3})
+	replacePiece(game, piece.PieceO)
+	game.Update(HardDrop)
+
+	squares := game.Board().Squares()
+
+	for y := 0; y < 2; y++ {
+		for x, square := range squares[y] {
+			if x == 3 && square != nil {
+				t.Fatalf("hole at (%d, %d) is filled", x, y)
+			}
+
+			if x != 3 && (square == nil || square.Type() != piece.Garbage) {
+				t.Fatalf("square (%d, %d) isn't garbage", x, y)
+			}
+		}
+	}
+
+	for _, position := range [][2]int{{4, 2}, {5, 2}, {4, 3}, {5, 3}} {
+		square := squares[position[1]][position[0]]
+
+		if square == nil || square.Type() != piece.PieceO {
+			t.Fatalf("the O piece wasn't pushed up to (%d, %d)", position[0], position[1])
+		}
+	}
+
+	if game.PendingGarbage() != 0 {
+		t.Fatalf("%d rows still pending", game.PendingGarbage())
+	}
+}
+
+func TestLockCancelsGarbage(t *testing.T) {
+	tests := []struct {
+		queued  int
+		attack  int
+		pending int
+	}{
+		{0, 1, 0},
+		{1, 0, 0},
+		{3, 0, 2},
+	}
+
+	for _, test := range tests {
+		game, _ := createTestGame(1)
+		game.QueueGarbage(Garbage{Lines: test.queued, Hole: 0})
+
+		// a double (attack 1) with the O piece, leaving a block so it isn't a perfect clear
+		fillRow(game, 0, 4, 5)
+		fillRow(game, 1, 4, 5)
+		fillRow(game, 2, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+		replacePiece(game, piece.PieceO)
+		game.Update(HardDrop)
+
+		result := game.LastClear()
+
+		if result.Lines != 2 || result.Attack != test.attack || game.PendingGarbage() != test.pending {
+			t.Fatalf("%d queued: got %d lines, attack %d and %d pending, expected 2 lines, attack %d and %d pending",
+				test.queued, result.Lines, result.Attack, game.PendingGarbage(), test.attack, test.pending)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package headless
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+const (
+	// DefaultFrame is the simulated time between two updates (60 updates per second)
+	DefaultFrame = time.Second / 60
+
+	scriptComment = "#"
+	scriptNoMove  = "-"
+)
+
+var inputMoves = []game.Move{
+	game.MoveLeft,
+	game.MoveRight,
+	game.RotateLeft,
+	game.RotateRight,
+	game.SoftDrop,
+	game.HardDrop,
+	game.Hold,
+}
+
+// Input decides the move to make on each update of a headless game
+type Input = func(current *game.Game) game.Move
+
+// Stats holds what happened during a headless game.
+// Clears counts the locked pieces by the number of lines they cleared.
+type Stats struct {
+	Score     int           `json:"score"`
+	Lines     int           `json:"lines"`
+	Level     int           `json:"level"`
+	Pieces    int           `json:"pieces"`
+	Clears    [5]int        `json:"clears"`
+	TSpins    int           `json:"tSpins"`
+	MaxCombo  int           `json:"maxCombo"`
+	Duration  time.Duration `json:"duration"`
+	ToppedOut bool          `json:"toppedOut"`
+	TimedOut  bool          `json:"timedOut"`
+}
+
+// Runner drives a game with a manual clock, without a window
+type Runner struct {
+	game  *game.Game
+	clock *game.ManualClock
+	input Input
+	frame time.Duration
+	stats Stats
+}
+
+// CreateRunner creates a headless game with the given settings, driven by the given input
+func CreateRunner(config game.Config, input Input) *Runner {
+	clock := game.CreateManualClock(time.Unix(0, 0))
+	config.Clock = clock
+
+	runner := &Runner{
+		game:  game.CreateGameWithConfig(config),
+		clock: clock,
+		input: input,
+		frame: DefaultFrame,
+	}
+
+	runner.game.Subscribe(runner.record)
+
+	return runner
+}
+
+// Game returns the game being run
+func (runner *Runner) Game() *game.Game {
+	return runner.game
+}
+
+// SetFrame changes the simulated time between two updates
+func (runner *Runner) SetFrame(frame time.Duration) {
+	runner.frame = frame
+}
+
+// Step advances the clock by one frame and updates the game with the next move
+func (runner *Runner) Step() {
+	runner.clock.Advance(runner.frame)
+	runner.game.Update(runner.input(runner.game))
+	runner.stats.Duration = runner.game.Elapsed()
+}
+
+// Run steps the game until it ends or the simulated time limit is reached (no limit if zero)
+func (runner *Runner) Run(limit time.Duration) Stats {
+	for runner.game.IsRunning() {
+		if limit > 0 && runner.game.Elapsed() >= limit {
+			runner.game.Update(game.Closed)
+			runner.stats.TimedOut = true
+			break
+		}
+
+		runner.Step()
+	}
+
+	return runner.Stats()
+}
+
+// Stats returns the statistics of the game so far
+func (runner *Runner) Stats() Stats {
+	stats := runner.stats
+	stats.Score = runner.game.Score()
+	stats.Lines = runner.game.Lines()
+	stats.Level = runner.game.Level()
+
+	return stats
+}
+
+// record updates the statistics with an event of the game
+func (runner *Runner) record(event game.Event) {
+	switch event := event.(type) {
+	case game.PieceLockedEvent:
+		runner.stats.Pieces++
+		runner.stats.Clears[event.Result.Lines]++
+
+		if event.Result.TSpin != game.TSpinNone {
+			runner.stats.TSpins++
+		}
+
+		if event.Result.Combo > runner.stats.MaxCombo {
+			runner.stats.MaxCombo = event.Result.Combo
+		}
+		break
+	case game.GameOverEvent:
+		runner.stats.ToppedOut = event.ToppedOut
+		break
+	}
+}
+
+// NoInput never makes a move, leaving the pieces to gravity
+func NoInput(current *game.Game) game.Move {
+	return game.NoMove
+}
+
+// RandomInput makes a random move on every update with the given probability
+func RandomInput(random *rand.Rand, probability float64) Input {
+	return func(current *game.Game) game.Move {
+		if random.Float64() >= probability {
+			return game.NoMove
+		}
+
+		return inputMoves[random.Intn(len(inputMoves))]
+	}
+}
+
+// ScriptedInput makes the given moves, one per update, starting over when they run out
+func ScriptedInput(moves []game.Move) Input {
+	next := 0
+
+	return func(current *game.Game) game.Move {
+		if len(moves) == 0 {
+			return game.NoMove
+		}
+
+		move := moves[next]
+		next = (next + 1) % len(moves)
+
+		return move
+	}
+}
+
+// ParseScript reads moves written one per line.
+// A "-" line makes no move for an update, and lines starting with "#" are ignored.
+func ParseScript(reader io.Reader) ([]game.Move, error) {
+	moves := []game.Move{}
+	scanner := bufio.NewScanner(reader)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, scriptComment) {
+			continue
+		}
+
+		if text == scriptNoMove {
+			moves = append(moves, game.NoMove)
+			continue
+		}
+
+		if !isInputMove(text) {
+			return nil, fmt.Errorf("line %d: unknown move %q", line, text)
+		}
+
+		moves = append(moves, text)
+	}
+
+	return moves, scanner.Err()
+}
+
+// isInputMove checks if a move can be made by a player
+func isInputMove(move game.Move) bool {
+	if move == game.MoveDown {
+		return true
+	}
+
+	for _, inputMove := range inputMoves {
+		if move == inputMove {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package headless
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+func TestParseScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		moves  []game.Move
+		failed bool
+	}{
+		{"empty", "", []game.Move{}, false},
+		{"moves", "MoveLeft\nHardDrop\n", []game.Move{game.MoveLeft, game.HardDrop}, false},
+		{"no moves and comments", "# wait\n-\n\n  RotateRight  \n", []game.Move{game.NoMove, game.RotateRight}, false},
+		{"move down", "MoveDown", []game.Move{game.MoveDown}, false},
+		{"unknown move", "MoveLeft\nJump\n", nil, true},
+		{"moves players can't make", "Closed", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moves, err := ParseScript(strings.NewReader(test.script))
+			if (err != nil) != test.failed {
+				t.Fatalf("got error %v, expected failure to be %t", err, test.failed)
+			}
+
+			if len(moves) != len(test.moves) {
+				t.Fatalf("got moves %v, expected %v", moves, test.moves)
+			}
+
+			for i := range moves {
+				if moves[i] != test.moves[i] {
+					t.Fatalf("got moves %v, expected %v", moves, test.moves)
+				}
+			}
+		})
+	}
+}
+
+func TestScriptedInputStartsOver(t *testing.T) {
+	input := ScriptedInput([]game.Move{game.MoveLeft, game.HardDrop})
+	expected := []game.Move{game.MoveLeft, game.HardDrop, game.MoveLeft, game.HardDrop, game.MoveLeft}
+
+	for i, move := range expected {
+		if got := input(nil); got != move {
+			t.Fatalf("move %d is %s, expected %s", i, got, move)
+		}
+	}
+
+	if ScriptedInput(nil)(nil) != game.NoMove {
+		t.Fatal("an empty script made a move")
+	}
+}
+
+func TestRunnerIsDeterministic(t *testing.T) {
+	run := func() Stats {
+		config := game.DefaultConfig()
+		config.Seed = 5
+
+		return CreateRunner(config, RandomInput(rand.New(rand.NewSource(5)), 0.2)).Run(0)
+	}
+
+	first, second := run(), run()
+
+	if first != second {
+		t.Fatalf("the same game ended differently: %+v and %+v", first, second)
+	}
+
+	if !first.ToppedOut || first.Pieces == 0 {
+		t.Fatalf("the game didn't play until it topped out: %+v", first)
+	}
+}
+
+func TestRunnerStopsAtTheLimit(t *testing.T) {
+	config := game.DefaultConfig()
+	config.Seed = 1
+
+	runner := CreateRunner(config, NoInput)
+	runner.SetFrame(time.Second)
+	stats := runner.Run(10 * time.Second)
+
+	if !stats.TimedOut || stats.ToppedOut || stats.Duration != 10*time.Second {
+		t.Fatalf("got %+v, expected the game to stop after 10 seconds", stats)
+	}
+
+	if runner.Game().IsRunning() {
+		t.Fatal("the game still runs after the limit")
+	}
+}
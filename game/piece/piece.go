@@ -40,6 +40,11 @@ func (piece *Piece) State() State {
 	return piece.state
 }
 
+// Type returns the piece's type
+func (piece *Piece) Type() block.Type {
+	return piece.blocks[0].Type()
+}
+
 // CreatePiece creates a new piece
 func CreatePiece(blocks []*block.Block) *Piece {
 	return &Piece{
@@ -26,29 +26,29 @@ const (
 	// PieceZ is a label for the Z piece
 	PieceZ = "PieceZ"
 
-	// NumPieces is the number of available pieces
-	NumPieces = 7
-)
+	// Garbage is a label for the blocks of garbage rows sent by an opponent
+	Garbage = "Garbage"
 
-var (
-	// PieceICoords holds PieceI's coordinates
-	PieceICoords = [][][]block.Position{{{-2, -1, 0, 1}, {0, 0, 0, 0}}, {{0, 0, 0, 0}, {1, 0, -1, -2}}, {{1, 0, -1, -2}, {-1, -1, -1, -1}}, {{-1, -1, -1, -1}, {-2, -1, 0, 1}}}
+	// NoPiece represents the absence of a piece
+	NoPiece = ""
 
-	// PieceJCoords holds PieceJ's coordinates
-	PieceJCoords = [][][]block.Position{{{-1, 0, 1, 1}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, -1}}, {{1, 0, -1, -1}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {-1, 0, 1, 1}}}
+	// NumPieces is the number of available pieces
+	NumPieces = 7
 
-	// PieceLCoords holds PieceL's coordinates
-	PieceLCoords = [][][]block.Position{{{-1, 0, 1, 1}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {1, 0, -1, -1}}, {{1, 0, -1, -1}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {-1, 0, 1, 1}}}
+	// NoKick is the kick index reported when a rotation was not possible
+	NoKick = -1
 
-	// PieceOCoords holds PieceO's coordinates
-	PieceOCoords = [][][]block.Position{{{-1, -1, 0, 0}, {0, -1, 0, -1}}, {{-1, -1, 0, 0}, {0, -1, 0, -1}}, {{-1, -1, 0, 0}, {0, -1, 0, -1}}, {{-1, -1, 0, 0}, {0, -1, 0, -1}}}
+	// SRS is the name of the Super Rotation System used by the guideline
+	SRS = "SRS"
 
-	// PieceSCoords holds PieceS's coordinates
-	PieceSCoords = [][][]block.Position{{{-2, -1, -1, 0}, {-1, -1, 0, 0}}, {{-2, -2, -1, -1}, {1, 0, 0, -1}}, {{0, -1, -1, -2}, {1, 1, 0, 0}}, {{0, 0, -1, -1}, {-1, 0, 0, 1}}}
+	// ARS is the name of the Arika Rotation System used by TGM
+	ARS = "ARS"
 
-	// PieceTCoords holds PieceT's coordinates
-	PieceTCoords = [][][]block.Position{{{-1, 0, 1, 0}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, 0}}, {{1, 0, -1, 0}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {-1, 0, 1, 0}}}
+	// NRS is the name of the classic Nintendo Rotation System
+	NRS = "Nintendo"
+)
 
-	// PieceZCoords holds PieceZ's coordinates
-	PieceZCoords = [][][]block.Position{{{-2, -1, -1, 0}, {0, 0, -1, -1}}, {{-1, -1, -2, -2}, {1, 0, 0, -1}}, {{0, -1, -1, -2}, {0, 0, 1, 1}}, {{-1, -1, 0, 0}, {-1, 0, 0, 1}}}
+var (
+	// Types holds the available piece types
+	Types = []block.Type{PieceI, PieceJ, PieceL, PieceO, PieceS, PieceT, PieceZ}
 )
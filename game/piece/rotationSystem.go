@@ -0,0 +1,128 @@
+package piece
+
+import (
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// RotationSystem decides how pieces spawn and rotate
+type RotationSystem interface {
+	// Name returns the name the rotation system is registered under
+	Name() string
+
+	// SpawnState returns the state pieces of a type spawn in
+	SpawnState(pieceType block.Type) State
+
+	// Coords returns the coordinates of a piece type's blocks in a state, relative to the piece's origin
+	Coords(pieceType block.Type, state State) [][]block.Position
+
+	// Kicks returns the offsets to try, in order, when rotating a piece type between two states
+	Kicks(pieceType block.Type, oldState, newState State) []Kick
+}
+
+// Kick is an offset tried when rotating a piece
+type Kick struct {
+	X block.Position
+	Y block.Position
+}
+
+// superRotationSystem is the guideline rotation system, with separate kicks for PieceI
+type superRotationSystem struct{}
+
+// arikaRotationSystem is the TGM rotation system (without the centre column exception)
+type arikaRotationSystem struct{}
+
+// nintendoRotationSystem is the classic rotation system, which never kicks
+type nintendoRotationSystem struct{}
+
+var (
+	rotationSystems = map[string]RotationSystem{
+		SRS: superRotationSystem{},
+		ARS: arikaRotationSystem{},
+		NRS: nintendoRotationSystem{},
+	}
+)
+
+// RegisterRotationSystem makes a rotation system available under its name, replacing any previous one
+func RegisterRotationSystem(rotationSystem RotationSystem) {
+	rotationSystems[rotationSystem.Name()] = rotationSystem
+}
+
+// GetRotationSystem returns the rotation system registered under a name (nil if there is none)
+func GetRotationSystem(name string) RotationSystem {
+	return rotationSystems[name]
+}
+
+// Name returns the rotation system's name
+func (rotationSystem superRotationSystem) Name() string {
+	return SRS
+}
+
+// SpawnState returns the state pieces of a type spawn in
+func (rotationSystem superRotationSystem) SpawnState(pieceType block.Type) State {
+	return NormalState
+}
+
+// Coords returns the coordinates of a piece type's blocks in a state
+func (rotationSystem superRotationSystem) Coords(pieceType block.Type, state State) [][]block.Position {
+	return srsCoords[pieceType][state]
+}
+
+// Kicks returns the offsets to try when rotating a piece type between two states
+func (rotationSystem superRotationSystem) Kicks(pieceType block.Type, oldState, newState State) []Kick {
+	var kicks []Kick
+
+	switch pieceType {
+	case PieceI:
+		kicks = srsIKicks[[2]State{oldState, newState}]
+	case PieceO:
+		kicks = noKicks
+	default:
+		kicks = srsKicks[[2]State{oldState, newState}]
+	}
+
+	return kicks
+}
+
+// Name returns the rotation system's name
+func (rotationSystem arikaRotationSystem) Name() string {
+	return ARS
+}
+
+// SpawnState returns the state pieces of a type spawn in
+func (rotationSystem arikaRotationSystem) SpawnState(pieceType block.Type) State {
+	return NormalState
+}
+
+// Coords returns the coordinates of a piece type's blocks in a state
+func (rotationSystem arikaRotationSystem) Coords(pieceType block.Type, state State) [][]block.Position {
+	return arsCoords[pieceType][state]
+}
+
+// Kicks returns the offsets to try when rotating a piece type between two states
+func (rotationSystem arikaRotationSystem) Kicks(pieceType block.Type, oldState, newState State) []Kick {
+	if pieceType == PieceI {
+		return noKicks
+	}
+
+	return arsKicks
+}
+
+// Name returns the rotation system's name
+func (rotationSystem nintendoRotationSystem) Name() string {
+	return NRS
+}
+
+// SpawnState returns the state pieces of a type spawn in
+func (rotationSystem nintendoRotationSystem) SpawnState(pieceType block.Type) State {
+	return NormalState
+}
+
+// Coords returns the coordinates of a piece type's blocks in a state
+func (rotationSystem nintendoRotationSystem) Coords(pieceType block.Type, state State) [][]block.Position {
+	return nrsCoords[pieceType][state]
+}
+
+// Kicks returns the offsets to try when rotating a piece type between two states
+func (rotationSystem nintendoRotationSystem) Kicks(pieceType block.Type, oldState, newState State) []Kick {
+	return noKicks
+}
@@ -0,0 +1,70 @@
+package piece
+
+import (
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+var (
+	// srsCoords holds the SRS coordinates of each piece type
+	srsCoords = map[block.Type][][][]block.Position{
+		PieceI: {{{-2, -1, 0, 1}, {0, 0, 0, 0}}, {{0, 0, 0, 0}, {1, 0, -1, -2}}, {{1, 0, -1, -2}, {-1, -1, -1, -1}}, {{-1, -1, -1, -1}, {-2, -1, 0, 1}}},
+		PieceJ: {{{1, 0, -1, -1}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {-1, 0, 1, 1}}, {{-1, 0, 1, 1}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, -1}}},
+		PieceL: {{{-1, 0, 1, 1}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {1, 0, -1, -1}}, {{1, 0, -1, -1}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {-1, 0, 1, 1}}},
+		PieceO: {{{-1, -1, 0, 0}, {0, -1, 0, -1}}, {{-1, -1, 0, 0}, {0, -1, 0, -1}}, {{-1, -1, 0, 0}, {0, -1, 0, -1}}, {{-1, -1, 0, 0}, {0, -1, 0, -1}}},
+		PieceS: {{{0, -1, -1, -2}, {1, 1, 0, 0}}, {{0, 0, -1, -1}, {-1, 0, 0, 1}}, {{-2, -1, -1, 0}, {-1, -1, 0, 0}}, {{-2, -2, -1, -1}, {1, 0, 0, -1}}},
+		PieceT: {{{1, 0, -1, 0}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {-1, 0, 1, 0}}, {{-1, 0, 1, 0}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, 0}}},
+		PieceZ: {{{0, -1, -1, -2}, {0, 0, 1, 1}}, {{-1, -1, 0, 0}, {-1, 0, 0, 1}}, {{-2, -1, -1, 0}, {0, 0, -1, -1}}, {{-1, -1, -2, -2}, {1, 0, 0, -1}}},
+	}
+
+	// arsCoords holds the ARS coordinates of each piece type
+	arsCoords = map[block.Type][][][]block.Position{
+		PieceI: {{{-2, -1, 0, 1}, {0, 0, 0, 0}}, {{0, 0, 0, 0}, {-2, -1, 0, 1}}, {{-2, -1, 0, 1}, {0, 0, 0, 0}}, {{0, 0, 0, 0}, {-2, -1, 0, 1}}},
+		PieceJ: {{{-2, -1, 0, 0}, {0, 0, 0, -1}}, {{-1, -1, -1, -2}, {1, 0, -1, -1}}, {{0, -1, -2, -2}, {-1, -1, -1, 0}}, {{-1, -1, -1, 0}, {-1, 0, 1, 1}}},
+		PieceL: {{{0, -1, -2, -2}, {0, 0, 0, -1}}, {{-1, -1, -1, -2}, {-1, 0, 1, 1}}, {{-2, -1, 0, 0}, {-1, -1, -1, 0}}, {{-1, -1, -1, 0}, {1, 0, -1, -1}}},
+		PieceO: {{{-1, 0, -1, 0}, {-1, -1, 0, 0}}, {{-1, 0, -1, 0}, {-1, -1, 0, 0}}, {{-1, 0, -1, 0}, {-1, -1, 0, 0}}, {{-1, 0, -1, 0}, {-1, -1, 0, 0}}},
+		PieceS: {{{-1, 0, -2, -1}, {0, 0, -1, -1}}, {{-2, -2, -1, -1}, {1, 0, 0, -1}}, {{-1, 0, -2, -1}, {0, 0, -1, -1}}, {{-2, -2, -1, -1}, {1, 0, 0, -1}}},
+		PieceT: {{{-2, -1, 0, -1}, {0, 0, 0, -1}}, {{-1, -1, -1, -2}, {-1, 0, 1, 0}}, {{-2, -1, 0, -1}, {-1, -1, -1, 0}}, {{-1, -1, -1, 0}, {-1, 0, 1, 0}}},
+		PieceZ: {{{-2, -1, -1, 0}, {0, 0, -1, -1}}, {{0, 0, -1, -1}, {1, 0, 0, -1}}, {{-2, -1, -1, 0}, {0, 0, -1, -1}}, {{0, 0, -1, -1}, {1, 0, 0, -1}}},
+	}
+
+	// nrsCoords holds the NRS coordinates of each piece type
+	nrsCoords = map[block.Type][][][]block.Position{
+		PieceI: {{{-2, -1, 0, 1}, {0, 0, 0, 0}}, {{0, 0, 0, 0}, {1, 0, -1, -2}}, {{-2, -1, 0, 1}, {0, 0, 0, 0}}, {{0, 0, 0, 0}, {1, 0, -1, -2}}},
+		PieceJ: {{{-1, 0, 1, 1}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, -1}}, {{1, 0, -1, -1}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {-1, 0, 1, 1}}},
+		PieceL: {{{-1, 0, 1, -1}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, 1}}, {{1, 0, -1, 1}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {-1, 0, 1, -1}}},
+		PieceO: {{{-1, 0, -1, 0}, {0, 0, -1, -1}}, {{-1, 0, -1, 0}, {0, 0, -1, -1}}, {{-1, 0, -1, 0}, {0, 0, -1, -1}}, {{-1, 0, -1, 0}, {0, 0, -1, -1}}},
+		PieceS: {{{0, 1, -1, 0}, {0, 0, -1, -1}}, {{0, 0, 1, 1}, {1, 0, 0, -1}}, {{0, 1, -1, 0}, {0, 0, -1, -1}}, {{0, 0, 1, 1}, {1, 0, 0, -1}}},
+		PieceT: {{{-1, 0, 1, 0}, {0, 0, 0, -1}}, {{0, 0, 0, -1}, {1, 0, -1, 0}}, {{-1, 0, 1, 0}, {0, 0, 0, 1}}, {{0, 0, 0, 1}, {1, 0, -1, 0}}},
+		PieceZ: {{{-1, 0, 0, 1}, {0, 0, -1, -1}}, {{1, 1, 0, 0}, {1, 0, 0, -1}}, {{-1, 0, 0, 1}, {0, 0, -1, -1}}, {{1, 1, 0, 0}, {1, 0, 0, -1}}},
+	}
+
+	// srsIKicks holds PieceI's SRS kicks for each rotation
+	srsIKicks = map[[2]State][]Kick{
+		{NormalState, RightState}:   {{0, 0}, {-2, 0}, {1, 0}, {-2, -1}, {1, 2}},
+		{RightState, NormalState}:   {{0, 0}, {2, 0}, {-1, 0}, {2, 1}, {-1, -2}},
+		{RightState, InvertedState}: {{0, 0}, {-1, 0}, {2, 0}, {-1, 2}, {2, -1}},
+		{InvertedState, RightState}: {{0, 0}, {1, 0}, {-2, 0}, {1, -2}, {-2, 1}},
+		{InvertedState, LeftState}:  {{0, 0}, {2, 0}, {-1, 0}, {2, 1}, {-1, -2}},
+		{LeftState, InvertedState}:  {{0, 0}, {-2, 0}, {1, 0}, {-2, -1}, {1, 2}},
+		{LeftState, NormalState}:    {{0, 0}, {1, 0}, {-2, 0}, {1, -2}, {-2, 1}},
+		{NormalState, LeftState}:    {{0, 0}, {-1, 0}, {2, 0}, {-1, 2}, {2, -1}},
+	}
+
+	// srsKicks holds the SRS kicks of PieceJ, PieceL, PieceS, PieceT and PieceZ for each rotation
+	srsKicks = map[[2]State][]Kick{
+		{NormalState, RightState}:   {{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}},
+		{RightState, NormalState}:   {{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}},
+		{RightState, InvertedState}: {{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}},
+		{InvertedState, RightState}: {{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}},
+		{InvertedState, LeftState}:  {{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}},
+		{LeftState, InvertedState}:  {{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}},
+		{LeftState, NormalState}:    {{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}},
+		{NormalState, LeftState}:    {{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}},
+	}
+
+	// arsKicks holds the ARS kicks (one column right, then left), which are not used by PieceI
+	arsKicks = []Kick{{0, 0}, {1, 0}, {-1, 0}}
+
+	// noKicks is used by rotations that are never kicked
+	noKicks = []Kick{{0, 0}}
+)
@@ -0,0 +1,313 @@
+package placement
+
+import (
+	"sort"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// pieceBlocks is the number of blocks of every piece
+const pieceBlocks = 4
+
+// searchMoves are the moves tried from every position of the piece
+var searchMoves = []game.Move{
+	game.MoveLeft,
+	game.MoveRight,
+	game.RotateRight,
+	game.RotateLeft,
+	game.MoveDown,
+}
+
+// Placement is a position where the current piece can lock, with the moves reaching it (ending with a hard drop).
+// Rotated tells if the piece gets there by rotating last, which only matters (and is only tracked) for T-spins.
+type Placement struct {
+	Type    block.Type
+	State   piece.State
+	Squares []game.Square
+	Rotated bool
+	Moves   []game.Move
+	trail   []node
+}
+
+// Plan follows the moves of a placement, searching again when the piece doesn't move as expected (because of gravity)
+type Plan struct {
+	target Placement
+	step   int
+}
+
+// landingKey identifies the squares of a placement (and whether it rotates last), whatever order its blocks are in
+type landingKey struct {
+	squares [pieceBlocks]game.Square
+	rotated bool
+}
+
+// node is a position of the piece during the search (its blocks in order, as rotations depend on it)
+type node struct {
+	positions [pieceBlocks][2]block.Position
+	state     piece.State
+	rotated   bool
+}
+
+// Find returns every placement the current piece of a game can reach, each with the shortest sequence of moves to it
+func Find(current *game.Game) []Placement {
+	if !current.IsRunning() || current.CurrentPiece() == nil {
+		return nil
+	}
+
+	return FindOnBoard(current.Board(), current.CurrentPiece(), current.RotationSystem())
+}
+
+// FindOnBoard returns every placement a piece on a board can reach, each with the shortest sequence of moves to it
+func FindOnBoard(original *board.Board, currentPiece *piece.Piece, rotationSystem piece.RotationSystem) []Placement {
+	scratch := copyBoard(original, currentPiece.Blocks())
+	start := createNode(currentPiece.Blocks(), currentPiece.State(), false)
+
+	return search(scratch, start, currentPiece.Type(), rotationSystem)
+}
+
+// CreatePlan creates a plan reaching a placement (which can come from elsewhere than Find, without moves)
+func CreatePlan(target Placement) *Plan {
+	return &Plan{
+		target: target,
+	}
+}
+
+// Target returns the placement the plan reaches
+func (plan *Plan) Target() Placement {
+	return plan.target
+}
+
+// NextMove returns the move to make on this update, or false if the placement can't be reached anymore
+func (plan *Plan) NextMove(current *game.Game) (game.Move, bool) {
+	currentPiece := current.CurrentPiece()
+	if currentPiece == nil {
+		return game.NoMove, false
+	}
+
+	if plan.step >= len(plan.target.trail) || plan.target.trail[plan.step].positions != createNode(currentPiece.Blocks(), 0, false).positions {
+		found := false
+
+		for _, placement := range Find(current) {
+			if placement.Matches(plan.target) {
+				plan.target = placement
+				plan.step = 0
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return game.NoMove, false
+		}
+	}
+
+	move := plan.target.Moves[plan.step]
+	plan.step++
+
+	return move, true
+}
+
+// Matches checks if two placements put the same piece on the same squares (and both rotate last or not)
+func (placement Placement) Matches(other Placement) bool {
+	if placement.Rotated != other.Rotated || len(placement.Squares) != len(other.Squares) {
+		return false
+	}
+
+	for i, square := range placement.Squares {
+		if square != other.Squares[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SortSquares orders squares the way placements hold them (bottom row first, then left to right), so they can be compared
+func SortSquares(squares []game.Square) {
+	sort.Slice(squares, func(i, j int) bool {
+		if squares[i].Y != squares[j].Y {
+			return squares[i].Y < squares[j].Y
+		}
+
+		return squares[i].X < squares[j].X
+	})
+}
+
+// search explores every position the piece can reach (breadth first), collecting where it lands from each of them
+func search(scratch *board.Board, start node, pieceType block.Type, rotationSystem piece.RotationSystem) []Placement {
+	parents := map[node]node{}
+	lastMoves := map[node]game.Move{start: game.NoMove}
+	queue := []node{start}
+	found := map[landingKey]bool{}
+	placements := []Placement{}
+
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+
+		blocks := from.blocks(pieceType)
+		scratch.SetSquares(blocks)
+		landing := createNode(scratch.ProjectBlocksDown(blocks), from.state, from.rotated && scratch.DropDistance(blocks) == 0)
+		scratch.ClearSquares(blocks)
+
+		placement := createPlacement(landing, pieceType)
+		key := placement.key()
+
+		if !found[key] {
+			found[key] = true
+			placement.trail, placement.Moves = trace(from, start, parents, lastMoves)
+			placements = append(placements, placement)
+		}
+
+		for _, move := range searchMoves {
+			next, ok := apply(scratch, from, move, pieceType, rotationSystem)
+			if !ok {
+				continue
+			}
+
+			if _, ok := lastMoves[next]; ok {
+				continue
+			}
+
+			parents[next] = from
+			lastMoves[next] = move
+			queue = append(queue, next)
+		}
+	}
+
+	return placements
+}
+
+// trace returns the positions leading to a position found by the search, and the moves between them (then a hard drop)
+func trace(position, start node, parents map[node]node, lastMoves map[node]game.Move) ([]node, []game.Move) {
+	trail := []node{position}
+	moves := []game.Move{game.HardDrop}
+
+	for position != start {
+		moves = append(moves, lastMoves[position])
+		position = parents[position]
+		trail = append(trail, position)
+	}
+
+	for i, j := 0, len(trail)-1; i < j; i, j = i+1, j-1 {
+		trail[i], trail[j] = trail[j], trail[i]
+		moves[i], moves[j] = moves[j], moves[i]
+	}
+
+	return trail, moves
+}
+
+// apply makes a move from a position on the scratch board, returning the new position if the move was possible
+func apply(scratch *board.Board, from node, move game.Move, pieceType block.Type, rotationSystem piece.RotationSystem) (node, bool) {
+	blocks := from.blocks(pieceType)
+	scratch.SetSquares(blocks)
+	defer scratch.ClearSquares(blocks)
+
+	state := from.state
+	ok := false
+
+	switch move {
+	case game.MoveLeft:
+		ok = scratch.MoveBlocksLeft(blocks)
+		break
+	case game.MoveRight:
+		ok = scratch.MoveBlocksRight(blocks)
+		break
+	case game.MoveDown:
+		ok = scratch.MoveBlocksDown(blocks)
+		break
+	case game.RotateRight:
+		kick := piece.NoKick
+		state, kick = scratch.RotateBlocksRight(blocks, state, rotationSystem)
+		ok = kick != piece.NoKick
+		break
+	case game.RotateLeft:
+		kick := piece.NoKick
+		state, kick = scratch.RotateBlocksLeft(blocks, state, rotationSystem)
+		ok = kick != piece.NoKick
+		break
+	}
+
+	rotated := (move == game.RotateRight || move == game.RotateLeft) && pieceType == piece.PieceT
+
+	return createNode(blocks, state, rotated), ok
+}
+
+// createNode records the position of some blocks
+func createNode(blocks []*block.Block, state piece.State, rotated bool) node {
+	position := node{
+		state:   state,
+		rotated: rotated,
+	}
+
+	for i, pieceBlock := range blocks {
+		position.positions[i] = [2]block.Position{pieceBlock.X(), pieceBlock.Y()}
+	}
+
+	return position
+}
+
+// blocks creates the blocks of a piece at a position
+func (position node) blocks(pieceType block.Type) []*block.Block {
+	blocks := make([]*block.Block, pieceBlocks)
+
+	for i, coords := range position.positions {
+		blocks[i] = block.CreateBlock(coords[0], coords[1], pieceType)
+	}
+
+	return blocks
+}
+
+// createPlacement creates the placement of a piece landing at a position (without the moves to it)
+func createPlacement(landing node, pieceType block.Type) Placement {
+	squares := make([]game.Square, 0, pieceBlocks)
+
+	for _, coords := range landing.positions {
+		squares = append(squares, game.Square{X: coords[0], Y: coords[1], Type: pieceType})
+	}
+
+	SortSquares(squares)
+
+	return Placement{
+		Type:    pieceType,
+		State:   landing.state,
+		Squares: squares,
+		Rotated: landing.rotated,
+	}
+}
+
+// key returns the key of a placement found by the search
+func (placement Placement) key() landingKey {
+	key := landingKey{rotated: placement.Rotated}
+	copy(key.squares[:], placement.Squares)
+
+	return key
+}
+
+// copyBoard copies the blocks of a board (but not the current piece), so moves can be tried on it
+func copyBoard(original *board.Board, currentBlocks []*block.Block) *board.Board {
+	scratch := board.CreateBoardWithDimensions(original.Width(), original.Height())
+
+	isPiece := func(square *block.Block) bool {
+		for _, pieceBlock := range currentBlocks {
+			if square == pieceBlock {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for y, row := range original.Squares() {
+		for x, square := range row {
+			if square != nil && !isPiece(square) {
+				scratch.SetSquares([]*block.Block{block.CreateBlock(x, y, square.Type())})
+			}
+		}
+	}
+
+	return scratch
+}
@@ -0,0 +1,127 @@
+package placement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+)
+
+// createGame creates a game whose clock never moves, so pieces only move when told to
+func createGame(seed int64, width, height board.Size) *game.Game {
+	config := game.DefaultConfig()
+	config.Seed = seed
+	config.Width = width
+	config.Height = height
+	config.Clock = game.CreateManualClock(time.Unix(0, 0))
+
+	return game.CreateGameWithConfig(config)
+}
+
+// lockedSquares returns the squares of a piece type on a board, leaving out the current piece
+func lockedSquares(current *game.Game, pieceType string) []game.Square {
+	squares := []game.Square{}
+	locked := copyBoard(current.Board(), current.CurrentPiece().Blocks())
+
+	for y, row := range locked.Squares() {
+		for x, square := range row {
+			if square != nil && square.Type() == pieceType {
+				squares = append(squares, game.Square{X: x, Y: y, Type: pieceType})
+			}
+		}
+	}
+
+	return squares
+}
+
+func TestPlacementCounts(t *testing.T) {
+	tests := []struct {
+		pieceType string
+		count     int
+	}{
+		{piece.PieceO, 9},
+		{piece.PieceI, 17},
+		{piece.PieceS, 17},
+		{piece.PieceZ, 17},
+	}
+
+	for _, test := range tests {
+		found := false
+
+		for seed := int64(0); seed < 100 && !found; seed++ {
+			current := createGame(seed, board.DefaultWidth, board.DefaultHeight)
+			if current.CurrentPiece().Type() != test.pieceType {
+				continue
+			}
+
+			found = true
+
+			placements := Find(current)
+			if len(placements) != test.count {
+				t.Fatalf("%s has %d placements on an empty board, expected %d", test.pieceType, len(placements), test.count)
+			}
+		}
+
+		if !found {
+			t.Fatalf("no seed starts with %s", test.pieceType)
+		}
+	}
+}
+
+func TestPlacementMovesReachTheirSquares(t *testing.T) {
+	tests := []struct {
+		width  board.Size
+		height board.Size
+	}{
+		{board.DefaultWidth, board.DefaultHeight},
+		{6, 12},
+	}
+
+	for _, test := range tests {
+		for seed := int64(0); seed < 20; seed++ {
+			for _, target := range Find(createGame(seed, test.width, test.height)) {
+				current := createGame(seed, test.width, test.height)
+
+				for _, move := range target.Moves {
+					current.Update(move)
+				}
+
+				squares := lockedSquares(current, target.Type)
+				candidate := Placement{Squares: squares, Rotated: target.Rotated}
+
+				if !candidate.Matches(target) {
+					t.Fatalf("%dx%d, seed %d: moves %v locked %v, expected %v", test.width, test.height, seed, target.Moves, squares, target.Squares)
+				}
+			}
+		}
+	}
+}
+
+func TestPlanSearchesAgainWhenThePieceMoves(t *testing.T) {
+	current := createGame(3, board.DefaultWidth, board.DefaultHeight)
+	placements := Find(current)
+	target := placements[len(placements)-1]
+	plan := CreatePlan(target)
+
+	// the piece falls a row before the plan starts, so the moves found at spawn no longer apply
+	current.Update(game.MoveDown)
+
+	for i := 0; i < 100; i++ {
+		move, ok := plan.NextMove(current)
+		if !ok {
+			t.Fatal("the placement was still reachable")
+		}
+
+		current.Update(move)
+
+		if move == game.HardDrop {
+			break
+		}
+	}
+
+	if !(Placement{Squares: lockedSquares(current, target.Type), Rotated: target.Rotated}).Matches(target) {
+		t.Fatal("the plan didn't reach its placement")
+	}
+}
@@ -0,0 +1,150 @@
+package game
+
+import (
+	"math/rand"
+
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+const (
+	// RandomizerPure picks every piece independently at random
+	RandomizerPure = "Pure"
+
+	// RandomizerBag deals the pieces from shuffled bags containing one of each piece
+	RandomizerBag = "Bag"
+
+	// RandomizerHistory rerolls pieces that were recently dealt (TGM style)
+	RandomizerHistory = "History"
+
+	historyRolls = 6
+)
+
+var (
+	historyStart      = []block.Type{piece.PieceZ, piece.PieceS, piece.PieceZ, piece.PieceS}
+	historyFirstTypes = []block.Type{piece.PieceI, piece.PieceJ, piece.PieceL, piece.PieceT}
+)
+
+// RandomizerType identifies a randomizer
+type RandomizerType = string
+
+// Randomizer generates the sequence of piece types
+type Randomizer interface {
+	// Next returns the next piece type
+	Next() block.Type
+}
+
+// PureRandomizer picks every piece independently at random
+type PureRandomizer struct {
+	random *rand.Rand
+}
+
+// BagRandomizer deals the pieces from shuffled bags containing one of each piece
+type BagRandomizer struct {
+	random *rand.Rand
+	bag    []block.Type
+}
+
+// HistoryRandomizer rerolls pieces found in the last few dealt pieces
+type HistoryRandomizer struct {
+	random  *rand.Rand
+	history []block.Type
+	first   bool
+}
+
+// CreateRandomizer creates a randomizer of the given type (pure random if the type is unknown)
+func CreateRandomizer(randomizerType RandomizerType, random *rand.Rand) Randomizer {
+	var randomizer Randomizer
+
+	switch randomizerType {
+	case RandomizerBag:
+		randomizer = CreateBagRandomizer(random)
+	case RandomizerHistory:
+		randomizer = CreateHistoryRandomizer(random)
+	default:
+		randomizer = CreatePureRandomizer(random)
+	}
+
+	return randomizer
+}
+
+// CreatePureRandomizer creates a new pure randomizer
+func CreatePureRandomizer(random *rand.Rand) *PureRandomizer {
+	return &PureRandomizer{
+		random: random,
+	}
+}
+
+// Next returns the next piece type
+func (randomizer *PureRandomizer) Next() block.Type {
+	return piece.Types[randomizer.random.Intn(piece.NumPieces)]
+}
+
+// CreateBagRandomizer creates a new 7-bag randomizer
+func CreateBagRandomizer(random *rand.Rand) *BagRandomizer {
+	return &BagRandomizer{
+		random: random,
+	}
+}
+
+// Next returns the next piece type
+func (randomizer *BagRandomizer) Next() block.Type {
+	if len(randomizer.bag) == 0 {
+		randomizer.bag = make([]block.Type, piece.NumPieces)
+		copy(randomizer.bag, piece.Types)
+
+		randomizer.random.Shuffle(len(randomizer.bag), func(i, j int) {
+			randomizer.bag[i], randomizer.bag[j] = randomizer.bag[j], randomizer.bag[i]
+		})
+	}
+
+	pieceType := randomizer.bag[0]
+	randomizer.bag = randomizer.bag[1:]
+
+	return pieceType
+}
+
+// CreateHistoryRandomizer creates a new history randomizer
+func CreateHistoryRandomizer(random *rand.Rand) *HistoryRandomizer {
+	history := make([]block.Type, len(historyStart))
+	copy(history, historyStart)
+
+	return &HistoryRandomizer{
+		random:  random,
+		history: history,
+		first:   true,
+	}
+}
+
+// Next returns the next piece type
+func (randomizer *HistoryRandomizer) Next() block.Type {
+	var pieceType block.Type
+
+	if randomizer.first {
+		randomizer.first = false
+		pieceType = historyFirstTypes[randomizer.random.Intn(len(historyFirstTypes))]
+	} else {
+		for roll := 0; roll < historyRolls; roll++ {
+			pieceType = piece.Types[randomizer.random.Intn(piece.NumPieces)]
+
+			if !randomizer.inHistory(pieceType) {
+				break
+			}
+		}
+	}
+
+	randomizer.history = append(randomizer.history[1:], pieceType)
+
+	return pieceType
+}
+
+// inHistory checks if a piece type was recently dealt
+func (randomizer *HistoryRandomizer) inHistory(pieceType block.Type) bool {
+	for _, historyType := range randomizer.history {
+		if historyType == pieceType {
+			return true
+		}
+	}
+
+	return false
+}
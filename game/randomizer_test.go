@@ -0,0 +1,98 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// deal takes some piece types from a new randomizer
+func deal(randomizerType RandomizerType, seed int64, count int) []block.Type {
+	randomizer := CreateRandomizer(randomizerType, rand.New(rand.NewSource(seed)))
+	pieces := make([]block.Type, count)
+
+	for i := range pieces {
+		pieces[i] = randomizer.Next()
+	}
+
+	return pieces
+}
+
+func TestBagRandomizerDealsEveryPieceOncePerBag(t *testing.T) {
+	pieces := deal(RandomizerBag, 7, piece.NumPieces*20)
+
+	for start := 0; start < len(pieces); start += piece.NumPieces {
+		seen := map[block.Type]bool{}
+
+		for _, pieceType := range pieces[start : start+piece.NumPieces] {
+			if seen[pieceType] {
+				t.Fatalf("bag starting at piece %d deals %s twice", start, pieceType)
+			}
+
+			seen[pieceType] = true
+		}
+	}
+}
+
+func TestHistoryRandomizerNeverStartsWithAnOverhang(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		first := deal(RandomizerHistory, seed, 1)[0]
+
+		if first == piece.PieceS || first == piece.PieceZ || first == piece.PieceO {
+			t.Fatalf("seed %d starts with %s", seed, first)
+		}
+	}
+}
+
+func TestUnknownRandomizerIsPure(t *testing.T) {
+	if _, ok := CreateRandomizer("Unknown", rand.New(rand.NewSource(1))).(*PureRandomizer); !ok {
+		t.Fatal("unknown randomizer types should be pure")
+	}
+}
+
+func TestRandomizersAreSeeded(t *testing.T) {
+	for _, randomizerType := range []RandomizerType{RandomizerPure, RandomizerBag, RandomizerHistory} {
+		t.Run(randomizerType, func(t *testing.T) {
+			first := deal(randomizerType, 42, 200)
+			second := deal(randomizerType, 42, 200)
+			other := deal(randomizerType, 43, 200)
+
+			same := true
+			for i := range first {
+				if first[i] != second[i] {
+					t.Fatalf("piece %d differs with the same seed: %s and %s", i, first[i], second[i])
+				}
+
+				same = same && first[i] == other[i]
+			}
+
+			if same {
+				t.Fatal("different seeds dealt the same pieces")
+			}
+		})
+	}
+}
+
+func TestGamesWithTheSameSeedDealTheSamePieces(t *testing.T) {
+	config := DefaultConfig()
+	config.Seed = 42
+	config.Randomizer = RandomizerBag
+
+	first := CreateGameWithConfig(config)
+	second := CreateGameWithConfig(config)
+
+	for i := 0; i < 50; i++ {
+		if first.CurrentPiece().Type() != second.CurrentPiece().Type() {
+			t.Fatalf("piece %d differs: %s and %s", i, first.CurrentPiece().Type(), second.CurrentPiece().Type())
+		}
+
+		first.Update(HardDrop)
+		second.Update(HardDrop)
+
+		if !first.IsRunning() || !second.IsRunning() {
+			break
+		}
+	}
+}
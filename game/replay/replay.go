@@ -0,0 +1,188 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/board"
+)
+
+const (
+	// Version is the version of the replay format written by this package
+	Version = 1
+)
+
+// Frame is a single update of the game, timed from the start of the game
+type Frame struct {
+	Time time.Duration `json:"t"`
+	Move game.Move     `json:"m,omitempty"`
+}
+
+// Replay holds everything needed to reproduce a game: its settings and every update it received.
+// Score, Lines and Board are how the game ended, so playing it back can be checked against them
+// (Board is missing from replays recorded before it was added).
+type Replay struct {
+	Version int          `json:"version"`
+	Config  game.Config  `json:"config"`
+	Frames  []Frame      `json:"frames"`
+	Score   int          `json:"score"`
+	Lines   int          `json:"lines"`
+	Board   *board.Board `json:"board,omitempty"`
+}
+
+// Recorder drives a game with its own clock and records every update
+type Recorder struct {
+	game   *game.Game
+	clock  *game.ManualClock
+	start  time.Time
+	replay *Replay
+}
+
+// Player plays a replay back through a headless game
+type Player struct {
+	game   *game.Game
+	clock  *game.ManualClock
+	start  time.Time
+	replay *Replay
+	next   int
+}
+
+// CreateRecorder creates a game with the given settings and starts recording it
+func CreateRecorder(config game.Config) *Recorder {
+	start := time.Now()
+	clock := game.CreateManualClock(start)
+	config.Clock = clock
+
+	return &Recorder{
+		game:  game.CreateGameWithConfig(config),
+		clock: clock,
+		start: start,
+		replay: &Replay{
+			Version: Version,
+			Config:  config,
+			Frames:  make([]Frame, 0),
+		},
+	}
+}
+
+// Game returns the game being recorded
+func (recorder *Recorder) Game() *game.Game {
+	return recorder.game
+}
+
+// Update moves the game's clock to the current time, records the move and updates the game
+func (recorder *Recorder) Update(move game.Move) {
+	recorder.clock.Set(time.Now())
+
+	recorder.replay.Frames = append(recorder.replay.Frames, Frame{
+		Time: recorder.clock.Now().Sub(recorder.start),
+		Move: move,
+	})
+
+	recorder.game.Update(move)
+}
+
+// Replay returns the recording so far, with the game's current score, lines and board
+func (recorder *Recorder) Replay() *Replay {
+	recorder.replay.Score = recorder.game.Score()
+	recorder.replay.Lines = recorder.game.Lines()
+	recorder.replay.Board = recorder.game.Board().Copy()
+
+	return recorder.replay
+}
+
+// Save writes a replay to a file
+func (replay *Replay) Save(path string) error {
+	data, err := json.Marshal(replay)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// Load reads a replay from a file
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replay := &Replay{}
+
+	err = json.Unmarshal(data, replay)
+	if err != nil {
+		return nil, err
+	}
+
+	if replay.Version != Version {
+		return nil, fmt.Errorf("unsupported replay version %d (expected %d)", replay.Version, Version)
+	}
+
+	return replay, nil
+}
+
+// CreatePlayer creates a headless game ready to play a replay back
+func CreatePlayer(replay *Replay) *Player {
+	start := time.Unix(0, 0)
+	clock := game.CreateManualClock(start)
+
+	config := replay.Config
+	config.Clock = clock
+
+	return &Player{
+		game:   game.CreateGameWithConfig(config),
+		clock:  clock,
+		start:  start,
+		replay: replay,
+	}
+}
+
+// Game returns the game the replay is played through
+func (player *Player) Game() *game.Game {
+	return player.game
+}
+
+// Done checks if every frame was played
+func (player *Player) Done() bool {
+	return player.next >= len(player.replay.Frames)
+}
+
+// Step plays the next frame
+func (player *Player) Step() {
+	if player.Done() {
+		return
+	}
+
+	frame := player.replay.Frames[player.next]
+	player.next++
+
+	player.clock.Set(player.start.Add(frame.Time))
+	player.game.Update(frame.Move)
+}
+
+// AdvanceTo plays every frame up to some time from the start of the game
+func (player *Player) AdvanceTo(elapsed time.Duration) {
+	for !player.Done() && player.replay.Frames[player.next].Time <= elapsed {
+		player.Step()
+	}
+}
+
+// Run plays every remaining frame
+func (player *Player) Run() {
+	for !player.Done() {
+		player.Step()
+	}
+}
+
+// Matches checks if the game reached the score, lines and board recorded in the replay
+func (player *Player) Matches() bool {
+	if player.game.Score() != player.replay.Score || player.game.Lines() != player.replay.Lines {
+		return false
+	}
+
+	return player.replay.Board == nil || player.game.Board().Equal(player.replay.Board)
+}
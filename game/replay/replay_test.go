@@ -0,0 +1,88 @@
+package replay
+
+import (
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// record records a game played with random moves
+func record(seed int64, frames int) *Replay {
+	config := game.DefaultConfig()
+	config.Seed = seed
+
+	recorder := CreateRecorder(config)
+	random := rand.New(rand.NewSource(seed))
+	moves := []game.Move{game.NoMove, game.MoveLeft, game.MoveRight, game.RotateRight, game.RotateLeft, game.Hold, game.HardDrop}
+
+	for i := 0; i < frames && recorder.Game().IsRunning(); i++ {
+		recorder.Update(moves[random.Intn(len(moves))])
+	}
+
+	return recorder.Replay()
+}
+
+func TestReplayRoundTrip(t *testing.T) {
+	for seed := int64(1); seed <= 3; seed++ {
+		path := filepath.Join(t.TempDir(), "replay.json")
+
+		err := record(seed, 3000).Save(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		player := CreatePlayer(loaded)
+		player.Run()
+
+		if !player.Done() || !player.Matches() {
+			t.Fatalf("seed %d: replay diverged (score %d, lines %d)", seed, player.Game().Score(), player.Game().Lines())
+		}
+
+		if !player.Game().Board().Equal(loaded.Board) {
+			t.Fatalf("seed %d: boards differ", seed)
+		}
+	}
+}
+
+func TestMatchesChecksTheBoard(t *testing.T) {
+	recording := record(1, 500)
+
+	player := CreatePlayer(recording)
+	player.Run()
+
+	for x := 0; x < recording.Board.Width(); x++ {
+		if recording.Board.Squares()[0][x] == nil {
+			recording.Board.SetSquares([]*block.Block{block.CreateBlock(x, 0, piece.Garbage)})
+			break
+		}
+	}
+
+	if player.Matches() {
+		t.Fatal("a different board matched")
+	}
+}
+
+func TestLoadRejectsOtherVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "replay.json")
+	recording := record(1, 10)
+	recording.Version = Version + 1
+
+	err := recording.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Load(path)
+	if err == nil {
+		t.Fatal("loaded a replay of another version")
+	}
+}
@@ -0,0 +1,137 @@
+package game
+
+import (
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+const (
+	// ScorerGuideline scores locks following the guideline (T-spins, combos, back-to-back and perfect clears)
+	ScorerGuideline = "Guideline"
+
+	// ScorerClassic awards a flat amount for every cleared row
+	ScorerClassic = "Classic"
+
+	// TSpinNone means the lock was not a T-spin
+	TSpinNone = ""
+
+	// TSpinMini means the lock was a T-spin mini
+	TSpinMini = "Mini"
+
+	// TSpinFull means the lock was a full T-spin
+	TSpinFull = "Full"
+
+	classicRowScore = 20
+
+	comboScore = 50
+
+	backToBackNumerator = 3
+
+	backToBackDenominator = 2
+
+	perfectClearBackToBackTetrisScore = 3200
+)
+
+var (
+	lineScores         = []int{0, 100, 300, 500, 800}
+	tSpinScores        = []int{400, 800, 1200, 1600}
+	tSpinMiniScores    = []int{100, 200, 400}
+	perfectClearScores = []int{0, 800, 1200, 1800, 2000}
+)
+
+// ScorerType identifies a scorer
+type ScorerType = string
+
+// TSpinType tells which kind of T-spin a lock was
+type TSpinType = string
+
+// ClearResult describes what happened when a piece locked.
+// Attack is the garbage the lock sends to an opponent, once it cancelled the garbage queued into this game.
+type ClearResult struct {
+	PieceType    block.Type
+	Lines        int
+	Rows         []int
+	TSpin        TSpinType
+	Combo        int
+	BackToBack   bool
+	PerfectClear bool
+	Level        int
+	Points       int
+	Attack       int
+}
+
+// Scorer awards points for locked pieces
+type Scorer interface {
+	// Score returns the points a lock is worth
+	Score(result ClearResult) int
+}
+
+// GuidelineScorer scores locks following the guideline
+type GuidelineScorer struct{}
+
+// ClassicScorer awards a flat amount for every cleared row
+type ClassicScorer struct{}
+
+// CreateScorer creates a scorer of the given type (guideline if the type is unknown)
+func CreateScorer(scorerType ScorerType) Scorer {
+	var scorer Scorer
+
+	switch scorerType {
+	case ScorerClassic:
+		scorer = ClassicScorer{}
+	default:
+		scorer = GuidelineScorer{}
+	}
+
+	return scorer
+}
+
+// Difficult checks if a clear keeps a back-to-back chain going (tetrises and T-spins that clear lines)
+func (result ClearResult) Difficult() bool {
+	return result.Lines >= 4 || (result.Lines > 0 && result.TSpin != TSpinNone)
+}
+
+// Score returns the points a lock is worth, scaled by level
+func (scorer GuidelineScorer) Score(result ClearResult) int {
+	var scores []int
+
+	switch result.TSpin {
+	case TSpinFull:
+		scores = tSpinScores
+	case TSpinMini:
+		scores = tSpinMiniScores
+	default:
+		scores = lineScores
+	}
+
+	points := scores[clamp(result.Lines, len(scores)-1)]
+
+	if result.BackToBack {
+		points = points * backToBackNumerator / backToBackDenominator
+	}
+
+	points += comboScore * result.Combo
+
+	if result.PerfectClear {
+		if result.BackToBack && result.Lines >= 4 {
+			points += perfectClearBackToBackTetrisScore
+		} else {
+			points += perfectClearScores[clamp(result.Lines, len(perfectClearScores)-1)]
+		}
+	}
+
+	return points * result.Level
+}
+
+// Score returns the points a lock is worth
+func (scorer ClassicScorer) Score(result ClearResult) int {
+	return result.Lines * classicRowScore
+}
+
+// clamp limits an index to a maximum
+func clamp(index, max int) int {
+	if index > max {
+		return max
+	}
+
+	return index
+}
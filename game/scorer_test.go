@@ -0,0 +1,77 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestGuidelineScorer(t *testing.T) {
+	tests := []struct {
+		name   string
+		result ClearResult
+		points int
+	}{
+		{"nothing", ClearResult{Level: 1}, 0},
+		{"single", ClearResult{Lines: 1, Level: 1}, 100},
+		{"double", ClearResult{Lines: 2, Level: 1}, 300},
+		{"triple", ClearResult{Lines: 3, Level: 1}, 500},
+		{"tetris", ClearResult{Lines: 4, Level: 1}, 800},
+		{"tetris at level 3", ClearResult{Lines: 4, Level: 3}, 2400},
+		{"back-to-back tetris", ClearResult{Lines: 4, BackToBack: true, Level: 1}, 1200},
+		{"T-spin without lines", ClearResult{TSpin: TSpinFull, Level: 1}, 400},
+		{"T-spin double", ClearResult{Lines: 2, TSpin: TSpinFull, Level: 1}, 1200},
+		{"back-to-back T-spin triple", ClearResult{Lines: 3, TSpin: TSpinFull, BackToBack: true, Level: 1}, 2400},
+		{"T-spin mini without lines", ClearResult{TSpin: TSpinMini, Level: 1}, 100},
+		{"T-spin mini single", ClearResult{Lines: 1, TSpin: TSpinMini, Level: 1}, 200},
+		{"single in a combo of 3", ClearResult{Lines: 1, Combo: 3, Level: 1}, 250},
+		{"perfect clear single", ClearResult{Lines: 1, PerfectClear: true, Level: 1}, 900},
+		{"perfect clear tetris", ClearResult{Lines: 4, PerfectClear: true, Level: 1}, 2800},
+		{"back-to-back perfect clear tetris", ClearResult{Lines: 4, PerfectClear: true, BackToBack: true, Level: 1}, 4400},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			points := CreateScorer(ScorerGuideline).Score(test.result)
+
+			if points != test.points {
+				t.Fatalf("got %d points, expected %d", points, test.points)
+			}
+		})
+	}
+}
+
+func TestClassicScorer(t *testing.T) {
+	tests := []struct {
+		result ClearResult
+		points int
+	}{
+		{ClearResult{Level: 5}, 0},
+		{ClearResult{Lines: 1, Level: 5}, 20},
+		{ClearResult{Lines: 4, TSpin: TSpinFull, BackToBack: true, Level: 5}, 80},
+	}
+
+	for _, test := range tests {
+		points := CreateScorer(ScorerClassic).Score(test.result)
+
+		if points != test.points {
+			t.Fatalf("%+v: got %d points, expected %d", test.result, points, test.points)
+		}
+	}
+}
+
+func TestDifficultClears(t *testing.T) {
+	tests := []struct {
+		result    ClearResult
+		difficult bool
+	}{
+		{ClearResult{Lines: 3}, false},
+		{ClearResult{Lines: 4}, true},
+		{ClearResult{Lines: 1, TSpin: TSpinMini}, true},
+		{ClearResult{TSpin: TSpinFull}, false},
+	}
+
+	for _, test := range tests {
+		if test.result.Difficult() != test.difficult {
+			t.Fatalf("%+v: expected difficult to be %t", test.result, test.difficult)
+		}
+	}
+}
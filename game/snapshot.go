@@ -0,0 +1,74 @@
+package game
+
+import (
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// Square is a square of the board holding a block of some type
+type Square struct {
+	X    block.Position `json:"x"`
+	Y    block.Position `json:"y"`
+	Type block.Type     `json:"type"`
+}
+
+// Snapshot is a read-only copy of everything a frontend draws.
+// Squares holds the block type of every square, bottom row first (piece.NoPiece if empty),
+// including the blocks of the current piece.
+// Ghost holds the squares the current piece would land on if dropped (empty once the game is over).
+// Hint holds the squares suggested for the current piece (the game leaves it empty, for an AI to fill in).
+type Snapshot struct {
+	Width          board.Size           `json:"width"`
+	Height         board.Size           `json:"height"`
+	Squares        [][]block.Type       `json:"squares"`
+	Ghost          []Square             `json:"ghost"`
+	Hint           []Square             `json:"hint"`
+	Queue          []block.Type         `json:"queue"`
+	Held           block.Type           `json:"held"`
+	Score          int                  `json:"score"`
+	Level          int                  `json:"level"`
+	Lines          int                  `json:"lines"`
+	PendingGarbage int                  `json:"pendingGarbage"`
+	Paused         bool                 `json:"paused"`
+	Running        bool                 `json:"running"`
+	RotationSystem piece.RotationSystem `json:"-"`
+}
+
+// Snapshot copies the current state of the game
+func (game *Game) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Width:          game.board.Width(),
+		Height:         game.board.Height(),
+		Squares:        make([][]block.Type, game.board.Height()),
+		Ghost:          []Square{},
+		Hint:           []Square{},
+		Queue:          game.Queue(),
+		Held:           game.heldPiece,
+		Score:          game.score,
+		Level:          game.level,
+		Lines:          game.lines,
+		PendingGarbage: game.PendingGarbage(),
+		Paused:         game.paused,
+		Running:        game.running,
+		RotationSystem: game.rotationSystem,
+	}
+
+	for y, row := range game.board.Squares() {
+		snapshot.Squares[y] = make([]block.Type, game.board.Width())
+
+		for x, square := range row {
+			if square != nil {
+				snapshot.Squares[y][x] = square.Type()
+			}
+		}
+	}
+
+	if game.running && game.currentPiece != nil {
+		for _, ghost := range game.board.ProjectBlocksDown(game.currentPiece.Blocks()) {
+			snapshot.Ghost = append(snapshot.Ghost, Square{X: ghost.X(), Y: ghost.Y(), Type: ghost.Type()})
+		}
+	}
+
+	return snapshot
+}
@@ -0,0 +1,226 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// gameState is the JSON representation of a game.
+// The randomizer is restored by dealing the same number of pieces again from the seed.
+type gameState struct {
+	Config     Config        `json:"config"`
+	Board      *board.Board  `json:"board"`
+	Piece      *pieceState   `json:"piece"`
+	Queue      []block.Type  `json:"queue"`
+	Held       block.Type    `json:"held"`
+	CanHold    bool          `json:"canHold"`
+	Score      int           `json:"score"`
+	Level      int           `json:"level"`
+	Lines      int           `json:"lines"`
+	Combo      int           `json:"combo"`
+	BackToBack bool          `json:"backToBack"`
+	Dealt      int           `json:"dealt"`
+	Running    bool          `json:"running"`
+	Paused     bool          `json:"paused"`
+	Elapsed    time.Duration `json:"elapsed"`
+	SinceFall  time.Duration `json:"sinceFall"`
+	Locking    bool          `json:"locking"`
+	SinceLock  time.Duration `json:"sinceLock"`
+	LockResets int           `json:"lockResets"`
+	LowestRow  int           `json:"lowestRow"`
+	LastKick   int           `json:"lastKick"`
+	Garbage    []Garbage     `json:"garbage"`
+}
+
+// pieceState is the JSON representation of the current piece
+type pieceState struct {
+	Type   block.Type          `json:"type"`
+	State  piece.State         `json:"state"`
+	Blocks [][2]block.Position `json:"blocks"`
+}
+
+// MarshalJSON stores the game as JSON, so it can be resumed with RestoreGame
+func (game *Game) MarshalJSON() ([]byte, error) {
+	now := game.clock.Now()
+
+	state := gameState{
+		Config:     game.config,
+		Board:      game.board,
+		Queue:      game.queue,
+		Held:       game.heldPiece,
+		CanHold:    game.canHold,
+		Score:      game.score,
+		Level:      game.level,
+		Lines:      game.lines,
+		Combo:      game.combo,
+		BackToBack: game.backToBack,
+		Dealt:      game.dealt,
+		Running:    game.running,
+		Paused:     game.paused,
+		Elapsed:    now.Sub(game.startTime),
+		SinceFall:  now.Sub(game.lastTime),
+		Locking:    game.locking,
+		SinceLock:  now.Sub(game.lockTime),
+		LockResets: game.lockResets,
+		LowestRow:  game.lowestRow,
+		LastKick:   game.lastKick,
+		Garbage:    game.garbage,
+	}
+
+	if game.paused {
+		state.SinceFall = game.timeSinceLastMove
+		state.SinceLock = game.timeSinceLock
+	}
+
+	if game.currentPiece != nil {
+		state.Piece = &pieceState{
+			Type:   game.currentPiece.Type(),
+			State:  game.currentPiece.State(),
+			Blocks: make([][2]block.Position, 0, len(game.currentPiece.Blocks())),
+		}
+
+		for _, pieceBlock := range game.currentPiece.Blocks() {
+			state.Piece.Blocks = append(state.Piece.Blocks, [2]block.Position{pieceBlock.X(), pieceBlock.Y()})
+		}
+	}
+
+	return json.Marshal(state)
+}
+
+// RestoreGame resumes a game stored as JSON, using the given clock (the real clock if nil)
+func RestoreGame(data []byte, clock Clock) (*Game, error) {
+	state := gameState{}
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateState(state)
+	if err != nil {
+		return nil, err
+	}
+
+	config := state.Config
+	config.Clock = clock
+
+	game := createGame(config)
+	now := game.clock.Now()
+
+	for game.dealt < state.Dealt {
+		game.dealPiece()
+	}
+
+	game.board = state.Board
+	game.queue = append(game.queue, state.Queue...)
+	game.heldPiece = state.Held
+	game.canHold = state.CanHold
+	game.score = state.Score
+	game.level = state.Level
+	game.lines = state.Lines
+	game.combo = state.Combo
+	game.backToBack = state.BackToBack
+	game.running = state.Running
+	game.paused = state.Paused
+	game.startTime = now.Add(-state.Elapsed)
+	game.lastTime = now.Add(-state.SinceFall)
+	game.timeSinceLastMove = state.SinceFall
+	game.locking = state.Locking
+	game.lockTime = now.Add(-state.SinceLock)
+	game.timeSinceLock = state.SinceLock
+	game.lockResets = state.LockResets
+	game.lowestRow = state.LowestRow
+	game.lastKick = state.LastKick
+	game.garbage = state.Garbage
+
+	if state.Piece != nil {
+		if len(state.Piece.Blocks) != 4 {
+			return nil, fmt.Errorf("saved piece has %d blocks instead of 4", len(state.Piece.Blocks))
+		}
+
+		blocks := make([]*block.Block, 0, len(state.Piece.Blocks))
+
+		for _, position := range state.Piece.Blocks {
+			x, y := position[0], position[1]
+
+			if x < 0 || x >= game.board.Width() || y < 0 || y >= game.board.Height() {
+				return nil, errors.New("saved piece is outside the board")
+			}
+
+			square := game.board.Squares()[y][x]
+			if square == nil || square.Type() != state.Piece.Type {
+				return nil, errors.New("saved piece does not match the board")
+			}
+
+			for _, previous := range blocks {
+				if previous == square {
+					return nil, errors.New("saved piece has the same block twice")
+				}
+			}
+
+			blocks = append(blocks, square)
+		}
+
+		game.currentPiece = piece.CreatePiece(blocks)
+		game.currentPiece.SetState(state.Piece.State)
+	} else if game.running {
+		return nil, errors.New("saved game is running but has no piece")
+	}
+
+	return game, nil
+}
+
+// validateState checks the parts of a saved game that would make the restored game panic later
+func validateState(state gameState) error {
+	if state.Board == nil {
+		return errors.New("saved game has no board")
+	}
+
+	err := state.Config.Validate()
+	if err != nil {
+		return err
+	}
+
+	if state.Board.Width() != state.Config.Width || state.Board.Height() != state.Config.Height {
+		return fmt.Errorf("saved board is %dx%d instead of %dx%d", state.Board.Width(), state.Board.Height(), state.Config.Width, state.Config.Height)
+	}
+
+	for _, pieceType := range state.Queue {
+		if !isPieceType(pieceType) {
+			return fmt.Errorf("saved queue has an unknown piece %q", pieceType)
+		}
+	}
+
+	if state.Held != piece.NoPiece && !isPieceType(state.Held) {
+		return fmt.Errorf("saved held piece %q is unknown", state.Held)
+	}
+
+	if state.Piece != nil {
+		if !isPieceType(state.Piece.Type) {
+			return fmt.Errorf("saved piece %q is unknown", state.Piece.Type)
+		}
+
+		if state.Piece.State < 0 || state.Piece.State >= piece.NumStates {
+			return fmt.Errorf("saved piece has an unknown rotation state %d", state.Piece.State)
+		}
+	}
+
+	return nil
+}
+
+// isPieceType checks if a type is one of the seven pieces
+func isPieceType(pieceType block.Type) bool {
+	for _, known := range piece.Types {
+		if known == pieceType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game/board"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+)
+
+// randomMoves are the moves played by playRandomly
+var randomMoves = []Move{NoMove, NoMove, MoveLeft, MoveRight, RotateLeft, RotateRight, SoftDrop, Hold, HardDrop}
+
+// playRandomly updates a game with random moves, a frame apart
+func playRandomly(game *Game, clock *ManualClock, random *rand.Rand, frames int) {
+	for i := 0; i < frames; i++ {
+		clock.Advance(time.Second / 60)
+		game.Update(randomMoves[random.Intn(len(randomMoves))])
+	}
+}
+
+// sameGame checks if two games are in the same state
+func sameGame(t *testing.T, first, second *Game) {
+	t.Helper()
+
+	if first.Score() != second.Score() || first.Lines() != second.Lines() || first.IsRunning() != second.IsRunning() {
+		t.Fatalf("games differ: score %d and %d, lines %d and %d", first.Score(), second.Score(), first.Lines(), second.Lines())
+	}
+
+	if !first.Board().Equal(second.Board()) {
+		t.Fatal("boards differ")
+	}
+
+	if first.HeldPiece() != second.HeldPiece() || first.PendingGarbage() != second.PendingGarbage() {
+		t.Fatal("held pieces or garbage differ")
+	}
+
+	if len(first.Queue()) != len(second.Queue()) {
+		t.Fatal("queues differ")
+	}
+
+	for i, pieceType := range first.Queue() {
+		if second.Queue()[i] != pieceType {
+			t.Fatal("queues differ")
+		}
+	}
+}
+
+func TestRestoredGameContinuesTheSame(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		original, clock := createTestGame(seed)
+		random := rand.New(rand.NewSource(seed))
+
+		playRandomly(original, clock, random, 600)
+		original.QueueGarbage(Garbage{Lines: 2, Hole: 3})
+
+		data, err := json.Marshal(original)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		restoredClock := CreateManualClock(clock.Now())
+
+		restored, err := RestoreGame(data, restoredClock)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sameGame(t, original, restored)
+
+		playRandomly(original, clock, rand.New(rand.NewSource(seed*10)), 2000)
+		playRandomly(restored, restoredClock, rand.New(rand.NewSource(seed*10)), 2000)
+
+		sameGame(t, original, restored)
+	}
+}
+
+func TestRestoreGameRejectsBrokenSaves(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(state *gameState)
+	}{
+		{"no board", func(state *gameState) { state.Board = nil }},
+		{"running without a piece", func(state *gameState) { state.Piece = nil }},
+		{"piece without blocks", func(state *gameState) { state.Piece.Blocks = nil }},
+		{"piece with a block twice", func(state *gameState) { state.Piece.Blocks[1] = state.Piece.Blocks[0] }},
+		{"piece outside the board", func(state *gameState) { state.Piece.Blocks[0] = [2]block.Position{-1, 0} }},
+		{"piece not on the board", func(state *gameState) { state.Piece.Blocks[0] = [2]block.Position{0, 0} }},
+		{"piece in an unknown rotation state", func(state *gameState) { state.Piece.State = piece.NumStates }},
+		{"unknown piece", func(state *gameState) { state.Piece.Type = "PieceX" }},
+		{"unknown piece in the queue", func(state *gameState) { state.Queue[0] = "PieceX" }},
+		{"unknown held piece", func(state *gameState) { state.Held = "PieceX" }},
+		{"board too small for the pieces", func(state *gameState) { state.Config.Width = 2 }},
+		{"board of another size", func(state *gameState) { state.Board = board.CreateBoardWithDimensions(8, 16) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, _ := createTestGame(1)
+
+			data, err := json.Marshal(game)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			state := gameState{}
+
+			err = json.Unmarshal(data, &state)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			test.corrupt(&state)
+
+			data, err = json.Marshal(state)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = RestoreGame(data, nil)
+			if err == nil {
+				t.Fatal("the broken save was restored")
+			}
+		})
+	}
+}
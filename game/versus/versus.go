@@ -0,0 +1,127 @@
+package versus
+
+import (
+	"math/rand"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+const (
+	// Players is the number of players in a versus game
+	Players = 2
+
+	// NoWinner is returned by Winner while both players are alive (or if neither topped out)
+	NoWinner = -1
+
+	// holeSeedMix is mixed into the seed of a game to get the seed of its garbage holes
+	holeSeedMix uint64 = 0x9E3779B97F4A7C15
+)
+
+// Versus runs two games side by side, sending the lines each player clears to the other as garbage
+type Versus struct {
+	games  [Players]*game.Game
+	random *rand.Rand
+	winner int
+}
+
+// HoleSeed derives the seed of the garbage holes from the seed of a game, so the holes don't follow the pieces
+func HoleSeed(seed int64) int64 {
+	return int64(uint64(seed) ^ holeSeedMix)
+}
+
+// CreateVersus creates two games with the same settings (and so the same pieces)
+func CreateVersus(config game.Config) *Versus {
+	versus := &Versus{
+		random: rand.New(rand.NewSource(HoleSeed(config.Seed))),
+		winner: NoWinner,
+	}
+
+	for player := range versus.games {
+		player := player
+
+		versus.games[player] = game.CreateGameWithConfig(config)
+		versus.games[player].Subscribe(func(event game.Event) {
+			versus.handleEvent(player, event)
+		})
+	}
+
+	return versus
+}
+
+// Game returns the game of a player
+func (versus *Versus) Game(player int) *game.Game {
+	return versus.games[player]
+}
+
+// Games returns the games of both players
+func (versus *Versus) Games() [Players]*game.Game {
+	return versus.games
+}
+
+// IsRunning checks if both players are still playing
+func (versus *Versus) IsRunning() bool {
+	for _, current := range versus.games {
+		if !current.IsRunning() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Winner returns the player who won (NoWinner while the game is running)
+func (versus *Versus) Winner() int {
+	return versus.winner
+}
+
+// Update updates both games with the moves of each player.
+// Pausing or closing applies to both games.
+func (versus *Versus) Update(moves [Players]game.Move) {
+	for _, move := range moves {
+		if move == game.Closed || move == game.Paused {
+			moves = [Players]game.Move{move, move}
+		}
+	}
+
+	for player, current := range versus.games {
+		current.Update(moves[player])
+	}
+
+	for _, current := range versus.games {
+		if !current.IsRunning() {
+			versus.endAll()
+			break
+		}
+	}
+}
+
+// handleEvent sends garbage to the opponent when a player clears lines, and ends the match when a player tops out
+func (versus *Versus) handleEvent(player int, event game.Event) {
+	switch event := event.(type) {
+	case game.PieceLockedEvent:
+		lines := event.Result.Attack
+		opponent := versus.games[1-player]
+
+		if lines > 0 {
+			opponent.QueueGarbage(game.Garbage{
+				Lines: lines,
+				Hole:  versus.random.Intn(opponent.Board().Width()),
+			})
+		}
+		break
+	case game.GameOverEvent:
+		if event.ToppedOut && versus.winner == NoWinner {
+			versus.winner = 1 - player
+		}
+		break
+	}
+}
+
+// endAll ends every game that is still running
+func (versus *Versus) endAll() {
+	for _, current := range versus.games {
+		if current.IsRunning() {
+			current.Update(game.Closed)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package versus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+func TestHandleEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   game.Event
+		pending [Players]int
+		winner  int
+	}{
+		{"lock without attack", game.PieceLockedEvent{Result: game.ClearResult{Lines: 1}}, [Players]int{0, 0}, NoWinner},
+		{"attack", game.PieceLockedEvent{Result: game.ClearResult{Lines: 4, Attack: 4}}, [Players]int{0, 4}, NoWinner},
+		{"top out", game.GameOverEvent{ToppedOut: true}, [Players]int{0, 0}, 1},
+		{"closed", game.GameOverEvent{}, [Players]int{0, 0}, NoWinner},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := game.DefaultConfig()
+			config.Seed = 1
+			config.Clock = game.CreateManualClock(time.Unix(0, 0))
+
+			versus := CreateVersus(config)
+			versus.handleEvent(0, test.event)
+
+			for player, current := range versus.Games() {
+				if current.PendingGarbage() != test.pending[player] {
+					t.Fatalf("player %d has %d rows pending, expected %d", player, current.PendingGarbage(), test.pending[player])
+				}
+			}
+
+			if versus.Winner() != test.winner {
+				t.Fatalf("got winner %d, expected %d", versus.Winner(), test.winner)
+			}
+		})
+	}
+}
+
+func TestHoleSeedIsNotThePieceSeed(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		if HoleSeed(seed) == seed {
+			t.Fatalf("seed %d deals the holes from the seed of the pieces", seed)
+		}
+	}
+}
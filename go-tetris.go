@@ -1,25 +1,166 @@
 package main
 
 import (
+	"fmt"
+	"net"
+	"os"
+
 	"github.com/daplf/go-tetris/game"
-	"github.com/daplf/go-tetris/io/inputProcessor"
-	"github.com/daplf/go-tetris/io/renderer"
-	"github.com/faiface/pixel/pixelgl"
+	"github.com/daplf/go-tetris/game/ai"
+	"github.com/daplf/go-tetris/game/replay"
+	"github.com/daplf/go-tetris/game/versus"
+	"github.com/daplf/go-tetris/io/frontend"
+	"github.com/daplf/go-tetris/io/network"
 )
 
-func run() {
-	game := game.CreateGame()
-	renderer := renderer.CreateRenderer()
+// modes maps the optional first argument of the binary to the function running that mode
+var modes = map[string]func(args []string){
+	"terminal": runTerminal,
+	"serve":    runServe,
+}
+
+// defaultMode runs when no mode is given (the window, unless built with the nogl tag)
+var defaultMode = runTerminal
+
+// playOptions are the options shared by the modes playing a single game.
+// In a demo, the AI plays a new game (which is never saved), and hints show where the AI would place each piece.
+type playOptions struct {
+	recordPath      string
+	spectateAddress string
+	demo            bool
+	hint            bool
+	weights         ai.Weights
+}
+
+// demoInput lets the AI play, while the player can still pause or quit
+type demoInput struct {
+	player *ai.Player
+	input  frontend.InputSource
+}
+
+// GetInput returns the AI's move, unless the player paused or quit
+func (input demoInput) GetInput() game.Move {
+	move := input.input.GetInput()
+	if move == game.Paused || move == game.Closed {
+		return move
+	}
+
+	return input.player.GetInput()
+}
+
+// loadWeights reads the AI's weights from a file (the default weights if the path is empty), exiting on failure
+func loadWeights(path string) ai.Weights {
+	if path == "" {
+		return ai.DefaultWeights()
+	}
+
+	weights, err := ai.LoadWeights(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not load the AI's weights:", err)
+		os.Exit(1)
+	}
+
+	return weights
+}
+
+// playGame plays a new game (or resumes a saved one) until it ends, saving it if the player quits
+func playGame(saved *savedGame, options playOptions, renderer frontend.Renderer, input frontend.InputSource) {
+	recorder := replay.CreateRecorder(game.DefaultConfig())
+	update := recorder.Update
+	current := recorder.Game()
+
+	if saved != nil {
+		current = saved.game
+		update = saved.Update
+
+		if options.recordPath != "" {
+			fmt.Fprintln(os.Stderr, "resumed games can't be recorded")
+			options.recordPath = ""
+		}
+	}
+
+	if options.spectateAddress != "" {
+		update = shareGame(current, update, options.spectateAddress)
+	}
+
+	if options.demo {
+		input = demoInput{player: ai.CreatePlayer(ai.CreateAI(options.weights), current), input: input}
+	}
+
+	var hinter *ai.Hinter
+	if options.hint {
+		hinter = ai.CreateHinter(ai.CreateAI(options.weights))
+	}
+
+	move := game.NoMove
+
+	for current.IsRunning() {
+		snapshot := current.Snapshot()
+		if hinter != nil {
+			snapshot.Hint = hinter.Hint(current)
+		}
+
+		renderer.DrawFrame(snapshot)
+		move = input.GetInput()
+
+		if move == game.Closed && !options.demo {
+			err := saveGame(current)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "could not save game:", err)
+			}
+		}
+
+		update(move)
+	}
 
-	renderer.DrawBoard(game)
+	if move != game.Closed && !options.demo {
+		err := removeSavedGame()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
 
-	for game.IsRunning() {
-		renderer.DrawBoard(game)
-		move := inputProcessor.GetInput(renderer)
-		game.Update(move)
+	if options.recordPath != "" {
+		err := recorder.Replay().Save(options.recordPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	}
+}
+
+// shareGame lets spectators watch a game, returning an update function that also streams every frame to them
+func shareGame(current *game.Game, update func(move game.Move), address string) func(move game.Move) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not share the game:", err)
+		return update
+	}
+
+	broadcaster := network.CreateBroadcaster(current.Config(), []string{os.Getenv("USER")}, []*game.Game{current})
+	go network.ServeSpectators(listener, broadcaster)
+
+	return func(move game.Move) {
+		// spectators who just joined get the game before the update, which the frame then replays
+		broadcaster.SendSnapshots()
+		update(move)
+		broadcaster.Send(network.Message{Type: network.MessageFrame, Time: current.Elapsed(), Move: move})
+
+		if !current.IsRunning() {
+			broadcaster.Send(network.Message{Type: network.MessageEnd, Winner: versus.NoWinner})
+		}
 	}
 }
 
 func main() {
-	pixelgl.Run(run)
+	run := defaultMode
+	args := os.Args[1:]
+
+	if len(args) > 0 {
+		if mode, ok := modes[args[0]]; ok {
+			run = mode
+			args = args[1:]
+		}
+	}
+
+	run(args)
 }
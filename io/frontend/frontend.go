@@ -0,0 +1,17 @@
+package frontend
+
+import (
+	"github.com/daplf/go-tetris/game"
+)
+
+// Renderer draws a game
+type Renderer interface {
+	// DrawFrame draws the game as it is in the snapshot
+	DrawFrame(snapshot game.Snapshot)
+}
+
+// InputSource tells the game loop which moves the player makes
+type InputSource interface {
+	// GetInput checks if there is new input and returns it (game.Closed once the player quits)
+	GetInput() game.Move
+}
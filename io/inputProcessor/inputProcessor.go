@@ -2,77 +2,215 @@ package inputProcessor
 
 import (
 	"github.com/daplf/go-tetris/game"
-	"github.com/daplf/go-tetris/io/renderer"
+	"github.com/daplf/go-tetris/game/versus"
 	"github.com/faiface/pixel/pixelgl"
 )
 
+const (
+	// AnswerYes means a question was answered with yes
+	AnswerYes = "Yes"
+
+	// AnswerNo means a question was answered with no
+	AnswerNo = "No"
+
+	// AnswerClosed means the window was closed instead of answering
+	AnswerClosed = "Closed"
+
+	// NoAnswer means a question was not answered yet
+	NoAnswer = ""
+)
+
+// versusBindings holds the keys of each player of a versus game
+var versusBindings = [versus.Players]keyBindings{
+	{
+		moveLeft:    pixelgl.KeyA,
+		moveRight:   pixelgl.KeyD,
+		softDrop:    pixelgl.KeyS,
+		hardDrop:    pixelgl.KeyW,
+		rotateLeft:  pixelgl.KeyQ,
+		rotateRight: pixelgl.KeyE,
+		hold:        pixelgl.KeyLeftShift,
+	},
+	{
+		moveLeft:    pixelgl.KeyLeft,
+		moveRight:   pixelgl.KeyRight,
+		softDrop:    pixelgl.KeyDown,
+		hardDrop:    pixelgl.KeyUp,
+		rotateLeft:  pixelgl.KeyComma,
+		rotateRight: pixelgl.KeyPeriod,
+		hold:        pixelgl.KeyRightShift,
+	},
+}
+
+// Answer type
+type Answer = string
+
+// keyBindings maps the keys of one player to moves
+type keyBindings struct {
+	moveLeft    pixelgl.Button
+	moveRight   pixelgl.Button
+	softDrop    pixelgl.Button
+	hardDrop    pixelgl.Button
+	rotateLeft  pixelgl.Button
+	rotateRight pixelgl.Button
+	hold        pixelgl.Button
+}
+
+// InputProcessor reads the player's moves from a window
+type InputProcessor struct {
+	window *pixelgl.Window
+}
+
+// CreateInputProcessor creates an input processor reading from a window
+func CreateInputProcessor(window *pixelgl.Window) *InputProcessor {
+	return &InputProcessor{
+		window: window,
+	}
+}
+
+// GetAnswer checks if a yes or no question was answered (with the Y and N keys)
+func (inputProcessor *InputProcessor) GetAnswer() Answer {
+	answer := NoAnswer
+
+	if inputProcessor.window.JustPressed(pixelgl.KeyY) {
+		answer = AnswerYes
+	}
+
+	if inputProcessor.window.JustPressed(pixelgl.KeyN) {
+		answer = AnswerNo
+	}
+
+	if inputProcessor.window.Closed() {
+		answer = AnswerClosed
+	}
+
+	return answer
+}
+
 // GetInput checks if there is new input and returns it
-func GetInput(renderer *renderer.Renderer) game.Move {
-	move := getJustPressed(renderer)
+func (inputProcessor *InputProcessor) GetInput() game.Move {
+	move := inputProcessor.getJustPressed()
+
+	if move == game.NoMove {
+		move = inputProcessor.getRepeated()
+	}
 
 	if move == game.NoMove {
-		move = getRepeated(renderer)
+		move = inputProcessor.getHeld()
 	}
 
-	if renderer.Window().Closed() {
+	if inputProcessor.window.Closed() {
 		move = game.Closed
 	}
 
 	return move
 }
 
-func getJustPressed(renderer *renderer.Renderer) game.Move {
+func (inputProcessor *InputProcessor) getJustPressed() game.Move {
 	var move game.Move
 
-	if renderer.Window().JustPressed(pixelgl.KeyDown) {
-		move = game.MoveDown
+	if inputProcessor.window.JustPressed(pixelgl.KeySpace) {
+		move = game.HardDrop
 	}
 
-	if renderer.Window().JustPressed(pixelgl.KeyRight) {
+	if inputProcessor.window.JustPressed(pixelgl.KeyRight) {
 		move = game.MoveRight
 	}
 
-	if renderer.Window().JustPressed(pixelgl.KeyLeft) {
+	if inputProcessor.window.JustPressed(pixelgl.KeyLeft) {
 		move = game.MoveLeft
 	}
 
-	if renderer.Window().JustPressed(pixelgl.KeyA) || renderer.Window().JustPressed(pixelgl.KeyS) {
+	if inputProcessor.window.JustPressed(pixelgl.KeyA) || inputProcessor.window.JustPressed(pixelgl.KeyS) {
 		move = game.RotateLeft
 	}
 
-	if renderer.Window().JustPressed(pixelgl.KeyW) || renderer.Window().JustPressed(pixelgl.KeyD) {
+	if inputProcessor.window.JustPressed(pixelgl.KeyW) || inputProcessor.window.JustPressed(pixelgl.KeyD) {
 		move = game.RotateRight
 	}
 
-	if renderer.Window().JustPressed(pixelgl.KeyP) {
+	if inputProcessor.window.JustPressed(pixelgl.KeyC) || inputProcessor.window.JustPressed(pixelgl.KeyLeftShift) {
+		move = game.Hold
+	}
+
+	if inputProcessor.window.JustPressed(pixelgl.KeyP) {
 		move = game.Paused
 	}
 
 	return move
 }
 
-func getRepeated(renderer *renderer.Renderer) game.Move {
+func (inputProcessor *InputProcessor) getRepeated() game.Move {
 	var move game.Move
 
-	if renderer.Window().Repeated(pixelgl.KeyDown) {
-		move = game.MoveDown
-	}
-
-	if renderer.Window().Repeated(pixelgl.KeyRight) {
+	if inputProcessor.window.Repeated(pixelgl.KeyRight) {
 		move = game.MoveRight
 	}
 
-	if renderer.Window().Repeated(pixelgl.KeyLeft) {
+	if inputProcessor.window.Repeated(pixelgl.KeyLeft) {
 		move = game.MoveLeft
 	}
 
-	if renderer.Window().Repeated(pixelgl.KeyA) || renderer.Window().Repeated(pixelgl.KeyS) {
+	if inputProcessor.window.Repeated(pixelgl.KeyA) || inputProcessor.window.Repeated(pixelgl.KeyS) {
 		move = game.RotateLeft
 	}
 
-	if renderer.Window().Repeated(pixelgl.KeyW) || renderer.Window().Repeated(pixelgl.KeyD) {
+	if inputProcessor.window.Repeated(pixelgl.KeyW) || inputProcessor.window.Repeated(pixelgl.KeyD) {
 		move = game.RotateRight
 	}
 
 	return move
 }
+
+func (inputProcessor *InputProcessor) getHeld() game.Move {
+	var move game.Move
+
+	if inputProcessor.window.Pressed(pixelgl.KeyDown) {
+		move = game.SoftDrop
+	}
+
+	return move
+}
+
+// GetVersusInput checks if there is new input from each player of a versus game (P pauses both)
+func (inputProcessor *InputProcessor) GetVersusInput() [versus.Players]game.Move {
+	moves := [versus.Players]game.Move{}
+
+	for player, bindings := range versusBindings {
+		moves[player] = inputProcessor.getPlayerMove(bindings)
+
+		if inputProcessor.window.JustPressed(pixelgl.KeyP) {
+			moves[player] = game.Paused
+		}
+
+		if inputProcessor.window.Closed() {
+			moves[player] = game.Closed
+		}
+	}
+
+	return moves
+}
+
+// getPlayerMove checks if a player pressed, repeated or held one of their keys
+func (inputProcessor *InputProcessor) getPlayerMove(bindings keyBindings) game.Move {
+	window := inputProcessor.window
+
+	switch {
+	case window.JustPressed(bindings.hardDrop):
+		return game.HardDrop
+	case window.JustPressed(bindings.hold):
+		return game.Hold
+	case window.JustPressed(bindings.moveLeft) || window.Repeated(bindings.moveLeft):
+		return game.MoveLeft
+	case window.JustPressed(bindings.moveRight) || window.Repeated(bindings.moveRight):
+		return game.MoveRight
+	case window.JustPressed(bindings.rotateLeft) || window.Repeated(bindings.rotateLeft):
+		return game.RotateLeft
+	case window.JustPressed(bindings.rotateRight) || window.Repeated(bindings.rotateRight):
+		return game.RotateRight
+	case window.Pressed(bindings.softDrop):
+		return game.SoftDrop
+	}
+
+	return game.NoMove
+}
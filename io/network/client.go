@@ -0,0 +1,266 @@
+package network
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+)
+
+// Client plays a match on a server: it runs the player's game and a replica of the opponent's game,
+// rebuilt from the frames and garbage the opponent sends
+type Client struct {
+	conn          *Conn
+	messages      chan Message
+	err           error
+	player        int
+	players       []string
+	game          *game.Game
+	clock         *game.ManualClock
+	start         time.Time
+	opponent      *game.Game
+	opponentClock *game.ManualClock
+	opponentStart time.Time
+	random        *rand.Rand
+	outgoing      []Message
+	ended         bool
+	winner        int
+	desynced      bool
+}
+
+// Connect connects to a server and asks to join a room
+func Connect(address, room, name string) (*Client, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{
+		conn:     CreateConn(conn),
+		messages: make(chan Message, 256),
+		winner:   versus.NoWinner,
+	}
+
+	err = client.conn.Send(Message{Type: MessageJoin, Room: room, Name: name})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go client.receive()
+
+	return client, nil
+}
+
+// receive reads messages from the server until the connection closes
+func (client *Client) receive() {
+	defer close(client.messages)
+
+	for {
+		message, err := client.conn.Receive()
+		if err != nil {
+			return
+		}
+
+		client.messages <- message
+	}
+}
+
+// Started checks if the match started (the games are only available once it did)
+func (client *Client) Started() bool {
+	return client.game != nil
+}
+
+// Ended checks if the match ended (or the connection was lost)
+func (client *Client) Ended() bool {
+	return client.ended
+}
+
+// Err returns the error that ended the match early, if any
+func (client *Client) Err() error {
+	return client.err
+}
+
+// Player returns the index of this client's player
+func (client *Client) Player() int {
+	return client.player
+}
+
+// Players returns the names of the players, in player order
+func (client *Client) Players() []string {
+	return client.players
+}
+
+// Winner returns the index of the player who won (versus.NoWinner if nobody did)
+func (client *Client) Winner() int {
+	return client.winner
+}
+
+// Desynced checks if the opponent's replica ended with a different result than the opponent reported
+func (client *Client) Desynced() bool {
+	return client.desynced
+}
+
+// Game returns the game of this client's player
+func (client *Client) Game() *game.Game {
+	return client.game
+}
+
+// Opponent returns the replica of the opponent's game
+func (client *Client) Opponent() *game.Game {
+	return client.opponent
+}
+
+// Update handles the messages from the server and updates the player's game with a move
+func (client *Client) Update(move game.Move) {
+	client.handleMessages()
+
+	if client.ended || !client.Started() || !client.game.IsRunning() {
+		return
+	}
+
+	// a match can't be paused, and closing is done by closing the connection
+	if move == game.Paused || move == game.Closed {
+		move = game.NoMove
+	}
+
+	now := time.Now()
+	client.clock.Set(now)
+	client.game.Update(move)
+
+	client.send(Message{Type: MessageFrame, Time: now.Sub(client.start), Move: move})
+
+	for _, message := range client.outgoing {
+		client.send(message)
+	}
+
+	client.outgoing = nil
+}
+
+// Close leaves the match
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
+
+// handleMessages handles every message received since the last update
+func (client *Client) handleMessages() {
+	for {
+		select {
+		case message, ok := <-client.messages:
+			if !ok {
+				client.finish(errors.New("connection to the server lost"))
+				return
+			}
+
+			client.handleMessage(message)
+		default:
+			return
+		}
+	}
+}
+
+// handleMessage handles a message from the server
+func (client *Client) handleMessage(message Message) {
+	if !client.Started() && (message.Type == MessageFrame || message.Type == MessageGarbage || message.Type == MessageGameOver) {
+		return
+	}
+
+	switch message.Type {
+	case MessageStart:
+		client.startMatch(message)
+		break
+	case MessageFrame:
+		client.opponentClock.Set(client.opponentStart.Add(message.Time))
+		client.opponent.Update(message.Move)
+		break
+	case MessageGarbage:
+		client.opponent.QueueGarbage(game.Garbage{Lines: message.Lines, Hole: message.Hole})
+		break
+	case MessageAttack:
+		if client.Started() && client.game.IsRunning() {
+			client.game.QueueGarbage(game.Garbage{Lines: message.Lines, Hole: message.Hole})
+			client.send(Message{Type: MessageGarbage, Lines: message.Lines, Hole: message.Hole})
+		}
+		break
+	case MessageGameOver:
+		client.desynced = message.Score != client.opponent.Score() || message.Lines != client.opponent.Lines()
+		break
+	case MessageEnd:
+		client.winner = message.Winner
+		client.finish(nil)
+		break
+	case MessageError:
+		client.finish(errors.New(message.Error))
+		break
+	}
+}
+
+// startMatch creates the player's game and the opponent's replica with the settings of the match
+func (client *Client) startMatch(message Message) {
+	if message.Config == nil || client.Started() {
+		return
+	}
+
+	client.player = message.Player
+	client.players = message.Players
+	client.random = rand.New(rand.NewSource(versus.HoleSeed(message.Config.Seed) + int64(message.Player)))
+
+	client.start = time.Now()
+	client.clock = game.CreateManualClock(client.start)
+	config := *message.Config
+	config.Clock = client.clock
+	client.game = game.CreateGameWithConfig(config)
+	client.game.Subscribe(client.handleEvent)
+
+	client.opponentStart = time.Unix(0, 0)
+	client.opponentClock = game.CreateManualClock(client.opponentStart)
+	config.Clock = client.opponentClock
+	client.opponent = game.CreateGameWithConfig(config)
+}
+
+// handleEvent sends attacks and the game over of the player's game (after the frame that caused them)
+func (client *Client) handleEvent(event game.Event) {
+	switch event := event.(type) {
+	case game.PieceLockedEvent:
+		lines := event.Result.Attack
+
+		if lines > 0 {
+			client.outgoing = append(client.outgoing, Message{
+				Type:  MessageAttack,
+				Lines: lines,
+				Hole:  client.random.Intn(client.game.Board().Width()),
+			})
+		}
+		break
+	case game.GameOverEvent:
+		if event.ToppedOut {
+			client.outgoing = append(client.outgoing, Message{
+				Type:  MessageGameOver,
+				Score: client.game.Score(),
+				Lines: client.game.Lines(),
+			})
+		}
+		break
+	}
+}
+
+// send sends a message, ending the match if the connection is lost
+func (client *Client) send(message Message) {
+	err := client.conn.Send(message)
+	if err != nil {
+		client.finish(err)
+	}
+}
+
+// finish ends the match
+func (client *Client) finish(err error) {
+	if client.ended {
+		return
+	}
+
+	client.ended = true
+	client.err = err
+}
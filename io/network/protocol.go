@@ -0,0 +1,203 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+const (
+	// MessageJoin asks the server to join a room as a player
+	MessageJoin = "join"
+
+	// MessageWaiting tells a player the room is waiting for an opponent
+	MessageWaiting = "waiting"
+
+	// MessageStart starts a match, with the settings (and seed) both players use
+	MessageStart = "start"
+
+	// MessageFrame is an update of a player's game, timed from the start of the match
+	MessageFrame = "frame"
+
+	// MessageAttack sends garbage to the opponent
+	MessageAttack = "attack"
+
+	// MessageGarbage tells that garbage was queued into a player's game before their next frame
+	MessageGarbage = "garbage"
+
+	// MessageGameOver tells that a player's game ended, with its final score and lines
+	MessageGameOver = "gameover"
+
+	// MessageEnd ends a match, naming the winner
+	MessageEnd = "end"
+
+	// MessageSpectate asks to watch the games of a room (or of a shared local game) without playing
+	MessageSpectate = "spectate"
+
+	// MessageSnapshot sends a spectator the full state of a player's game, timed from the start of the match
+	MessageSnapshot = "snapshot"
+
+	// MessageError reports a problem (the server closes the connection afterwards)
+	MessageError = "error"
+
+	// DefaultPort is the port the server listens on by default
+	DefaultPort = "7777"
+
+	// maxMessageSize is the longest line a connection accepts
+	maxMessageSize = 1 << 20
+
+	// outboxSize is how many messages an outbox holds before its connection is dropped as too slow
+	outboxSize = 1024
+)
+
+// MessageType identifies a message
+type MessageType = string
+
+// Message is a line of the protocol (see PROTOCOL.md); only the fields of its type are set
+type Message struct {
+	Type    MessageType     `json:"type"`
+	Room    string          `json:"room,omitempty"`
+	Name    string          `json:"name,omitempty"`
+	Player  int             `json:"player,omitempty"`
+	Players []string        `json:"players,omitempty"`
+	Config  *game.Config    `json:"config,omitempty"`
+	Time    time.Duration   `json:"t,omitempty"`
+	Move    game.Move       `json:"m,omitempty"`
+	Lines   int             `json:"lines,omitempty"`
+	Hole    int             `json:"hole,omitempty"`
+	Score   int             `json:"score,omitempty"`
+	Winner  int             `json:"winner,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	State   json.RawMessage `json:"state,omitempty"`
+}
+
+// Conn sends and receives messages as JSON lines
+type Conn struct {
+	conn       net.Conn
+	scanner    *bufio.Scanner
+	writer     *bufio.Writer
+	writeMutex sync.Mutex
+}
+
+// Outbox writes the messages sent to a connection from its own goroutine, so senders never wait for the network.
+// A connection too slow to keep up with its outbox is closed.
+type Outbox struct {
+	conn     *Conn
+	mutex    sync.Mutex
+	messages chan Message
+	closed   bool
+}
+
+// CreateConn wraps a connection to send and receive messages
+func CreateConn(conn net.Conn) *Conn {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxMessageSize)
+
+	return &Conn{
+		conn:    conn,
+		scanner: scanner,
+		writer:  bufio.NewWriter(conn),
+	}
+}
+
+// Send writes a message (it is safe to call from several goroutines)
+func (conn *Conn) Send(message Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	conn.writer.Write(data)
+	conn.writer.WriteByte('\n')
+
+	return conn.writer.Flush()
+}
+
+// Receive waits for the next message
+func (conn *Conn) Receive() (Message, error) {
+	message := Message{}
+
+	if !conn.scanner.Scan() {
+		err := conn.scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+
+		return message, err
+	}
+
+	err := json.Unmarshal(conn.scanner.Bytes(), &message)
+
+	return message, err
+}
+
+// Close closes the connection
+func (conn *Conn) Close() error {
+	return conn.conn.Close()
+}
+
+// CreateOutbox creates an outbox writing to a connection, until it is closed
+func CreateOutbox(conn *Conn) *Outbox {
+	outbox := &Outbox{
+		conn:     conn,
+		messages: make(chan Message, outboxSize),
+	}
+
+	go outbox.write()
+
+	return outbox
+}
+
+// Send queues a message without waiting, returning false if the outbox is closed (or just got closed for being full)
+func (outbox *Outbox) Send(message Message) bool {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	if outbox.closed {
+		return false
+	}
+
+	select {
+	case outbox.messages <- message:
+		return true
+	default:
+		outbox.closeMessages()
+		outbox.conn.Close()
+
+		return false
+	}
+}
+
+// Close stops the outbox once the messages already queued are written (the connection stays open)
+func (outbox *Outbox) Close() {
+	outbox.mutex.Lock()
+	defer outbox.mutex.Unlock()
+
+	outbox.closeMessages()
+}
+
+// closeMessages closes the queue, once (the outbox must be locked)
+func (outbox *Outbox) closeMessages() {
+	if !outbox.closed {
+		outbox.closed = true
+		close(outbox.messages)
+	}
+}
+
+// write writes the queued messages until the outbox is closed, closing it and the connection if a write fails
+func (outbox *Outbox) write() {
+	for message := range outbox.messages {
+		if outbox.conn.Send(message) != nil {
+			outbox.conn.Close()
+			outbox.Close()
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package network
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+)
+
+// Server hosts rooms where two players play a match against each other
+type Server struct {
+	mutex  sync.Mutex
+	rooms  map[string]*room
+	config func() game.Config
+}
+
+// room holds the players of a match, and replicas of their games for the spectators.
+// Messages are written through an outbox per connection, so the room is never locked while waiting for the network.
+type room struct {
+	name        string
+	mutex       sync.Mutex
+	players     []*Outbox
+	names       []string
+	ended       bool
+	replicas    []*game.Game
+	clocks      []*game.ManualClock
+	broadcaster *Broadcaster
+	waiting     []*Outbox
+}
+
+// CreateServer creates a server starting every match with settings from the config function
+// (which should pick a new seed every time)
+func CreateServer(config func() game.Config) *Server {
+	return &Server{
+		rooms:  map[string]*room{},
+		config: config,
+	}
+}
+
+// Serve accepts connections until the listener fails
+func (server *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go server.handle(CreateConn(conn))
+	}
+}
+
+// handle runs a connection, from its join message until it closes
+func (server *Server) handle(conn *Conn) {
+	defer conn.Close()
+
+	message, err := conn.Receive()
+	if err != nil {
+		return
+	}
+
+	if message.Type == MessageSpectate {
+		server.spectate(message.Room, conn)
+		return
+	}
+
+	if message.Type != MessageJoin {
+		conn.Send(Message{Type: MessageError, Error: "expected a join message"})
+		return
+	}
+
+	room, player, err := server.join(message.Room, message.Name, conn)
+	if err != nil {
+		conn.Send(Message{Type: MessageError, Error: err.Error()})
+		return
+	}
+	defer server.leave(room, player)
+
+	for {
+		message, err := conn.Receive()
+		if err != nil {
+			return
+		}
+
+		room.relay(player, message)
+	}
+}
+
+// join adds a player to a room, starting the match once the room is full
+func (server *Server) join(name, playerName string, conn *Conn) (*room, int, error) {
+	// the room is locked before the server is unlocked, so a player leaving can't end it in between
+	server.mutex.Lock()
+	current, ok := server.rooms[name]
+	if ok {
+		current.mutex.Lock()
+
+		if !current.open() {
+			current.mutex.Unlock()
+			ok = false
+		}
+	}
+
+	if !ok {
+		current = &room{name: name}
+		current.mutex.Lock()
+		server.rooms[name] = current
+	}
+	server.mutex.Unlock()
+
+	defer current.mutex.Unlock()
+
+	if len(current.players) >= versus.Players {
+		return nil, 0, errors.New("room is full")
+	}
+
+	player := len(current.players)
+	outbox := CreateOutbox(conn)
+	current.players = append(current.players, outbox)
+	current.names = append(current.names, playerName)
+
+	if len(current.players) < versus.Players {
+		outbox.Send(Message{Type: MessageWaiting, Room: name})
+		return current, player, nil
+	}
+
+	config := server.config()
+	config.Clock = nil
+
+	for i, player := range current.players {
+		if player == nil {
+			continue
+		}
+
+		player.Send(Message{Type: MessageStart, Room: name, Player: i, Players: current.names, Config: &config})
+	}
+
+	current.startReplicas(config)
+
+	return current, player, nil
+}
+
+// open checks if players can still join a room: it hasn't ended and none of its players left (the room must be locked)
+func (current *room) open() bool {
+	if current.ended {
+		return false
+	}
+
+	for _, outbox := range current.players {
+		if outbox == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// spectate streams the games of a room to a spectator until it disconnects
+func (server *Server) spectate(name string, conn *Conn) {
+	server.mutex.Lock()
+	current, ok := server.rooms[name]
+	server.mutex.Unlock()
+
+	if !ok {
+		conn.Send(Message{Type: MessageError, Error: "no match in this room"})
+		return
+	}
+
+	outbox := CreateOutbox(conn)
+	defer outbox.Close()
+
+	current.mutex.Lock()
+	if current.broadcaster != nil {
+		current.broadcaster.Add(outbox)
+	} else {
+		current.waiting = append(current.waiting, outbox)
+	}
+	current.mutex.Unlock()
+
+	waitForLeave(conn)
+}
+
+// startReplicas creates the replicas of the players' games, and starts streaming them to the spectators
+func (current *room) startReplicas(config game.Config) {
+	for range current.players {
+		clock := game.CreateManualClock(time.Unix(0, 0))
+		config.Clock = clock
+
+		current.clocks = append(current.clocks, clock)
+		current.replicas = append(current.replicas, game.CreateGameWithConfig(config))
+	}
+
+	current.broadcaster = CreateBroadcaster(config, current.names, current.replicas)
+
+	for _, outbox := range current.waiting {
+		current.broadcaster.Add(outbox)
+	}
+
+	current.waiting = nil
+}
+
+// leave removes a player from its room, giving the match to the opponent if it was still being played
+func (server *Server) leave(current *room, player int) {
+	current.mutex.Lock()
+	outbox := current.players[player]
+	current.players[player] = nil
+	started := len(current.players) == versus.Players
+	current.mutex.Unlock()
+
+	outbox.Close()
+
+	if started {
+		current.end(versus.Players - 1 - player)
+	} else {
+		current.end(versus.NoWinner)
+	}
+
+	server.mutex.Lock()
+	if server.rooms[current.name] == current {
+		delete(server.rooms, current.name)
+	}
+	server.mutex.Unlock()
+}
+
+// relay sends the messages of a player's game to the opponent and the spectators, ending the match when the player tops out
+func (current *room) relay(player int, message Message) {
+	switch message.Type {
+	case MessageFrame, MessageGarbage, MessageAttack, MessageGameOver:
+		message.Player = player
+		current.send(player, message)
+
+		if message.Type == MessageGameOver {
+			current.end(versus.Players - 1 - player)
+		}
+		break
+	}
+}
+
+// send sends a message of a player to every other player, and applies it to the player's replica for the spectators
+func (current *room) send(player int, message Message) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+
+	for i, outbox := range current.players {
+		if i != player && outbox != nil {
+			outbox.Send(message)
+		}
+	}
+
+	if current.broadcaster == nil {
+		return
+	}
+
+	switch message.Type {
+	case MessageFrame:
+		current.broadcaster.SendSnapshots()
+		current.clocks[player].Set(time.Unix(0, 0).Add(message.Time))
+		current.replicas[player].Update(message.Move)
+		current.broadcaster.Send(message)
+		break
+	case MessageGarbage:
+		current.broadcaster.SendSnapshots()
+		current.replicas[player].QueueGarbage(game.Garbage{Lines: message.Lines, Hole: message.Hole})
+		current.broadcaster.Send(message)
+		break
+	}
+}
+
+// end tells every player who won, once
+func (current *room) end(winner int) {
+	current.mutex.Lock()
+	defer current.mutex.Unlock()
+
+	if current.ended {
+		return
+	}
+
+	current.ended = true
+
+	message := Message{Type: MessageEnd, Room: current.name, Winner: winner}
+
+	for _, outbox := range current.players {
+		if outbox != nil {
+			outbox.Send(message)
+		}
+	}
+
+	if current.broadcaster != nil {
+		current.broadcaster.SendSnapshots()
+		current.broadcaster.Send(message)
+	}
+
+	for _, outbox := range current.waiting {
+		outbox.Send(message)
+	}
+}
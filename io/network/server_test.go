@@ -0,0 +1,138 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/ai"
+)
+
+// startServer serves matches on a local port, with gravity too slow to move the pieces
+func startServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	server := CreateServer(func() game.Config {
+		config := game.DefaultConfig()
+		config.Seed = 7
+		config.GravityCurve = []time.Duration{time.Hour}
+
+		return config
+	})
+
+	go server.Serve(listener)
+
+	return listener.Addr().String()
+}
+
+// connect connects a client to a room
+func connect(t *testing.T, address, room, name string) *Client {
+	client, err := Connect(address, room, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+// waitForStart updates the clients until their match starts
+func waitForStart(t *testing.T, clients ...*Client) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		started := true
+
+		for _, client := range clients {
+			client.Update(game.NoMove)
+			started = started && client.Started()
+		}
+
+		if started {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("the match didn't start")
+}
+
+// sameGame checks if a replica caught up with the game it copies
+func sameGame(replica, original *game.Game) bool {
+	return replica.Board().Equal(original.Board()) &&
+		replica.Score() == original.Score() &&
+		replica.Lines() == original.Lines() &&
+		replica.PendingGarbage() == original.PendingGarbage()
+}
+
+func TestReplicaFollowsCancelledGarbage(t *testing.T) {
+	address := startServer(t)
+	a := connect(t, address, "room", "a")
+	b := connect(t, address, "room", "b")
+
+	waitForStart(t, a, b)
+
+	cancelled := 0
+	countCancelled := func(event game.Event) {
+		if locked, ok := event.(game.PieceLockedEvent); ok && game.Attack(locked.Result) > locked.Result.Attack {
+			cancelled++
+		}
+	}
+
+	a.Game().Subscribe(countCancelled)
+	b.Game().Subscribe(countCancelled)
+
+	players := []*ai.Player{ai.CreatePlayer(ai.CreateAI(ai.DefaultWeights()), nil), ai.CreatePlayer(ai.CreateAI(ai.DefaultWeights()), nil)}
+
+	for i := 0; i < 3000 && !a.Ended() && !b.Ended(); i++ {
+		a.Update(players[0].Input(a.Game()))
+		b.Update(players[1].Input(b.Game()))
+	}
+
+	if cancelled == 0 {
+		t.Fatal("no garbage was cancelled, so the match didn't test anything")
+	}
+
+	// b stops, so its game stays put while a receives the rest of its frames
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !sameGame(a.Opponent(), b.Game()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("the replica of b desynced (score %d, expected %d)", a.Opponent().Score(), b.Game().Score())
+		}
+
+		a.Update(game.NoMove)
+		time.Sleep(time.Millisecond)
+	}
+
+	if a.Desynced() || b.Desynced() {
+		t.Fatal("a game over didn't match its replica")
+	}
+}
+
+func TestAbandonedRoomIsReplaced(t *testing.T) {
+	address := startServer(t)
+	first := connect(t, address, "room", "first")
+
+	// the server needs time to read the join message, and then to notice the connection closed
+	time.Sleep(50 * time.Millisecond)
+	first.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	a := connect(t, address, "room", "a")
+	b := connect(t, address, "room", "b")
+
+	waitForStart(t, a, b)
+
+	if a.Player() == b.Player() || len(a.Players()) != 2 || a.Players()[0] != "a" {
+		t.Fatalf("got players %v, expected the new pair", a.Players())
+	}
+}
@@ -0,0 +1,292 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+)
+
+// Broadcaster streams games to spectators.
+// A new spectator first gets the settings and a snapshot of every game, then the frames and garbage that follow.
+// Messages are written through an outbox per spectator, so a slow spectator never holds up the games.
+type Broadcaster struct {
+	mutex      sync.Mutex
+	config     game.Config
+	players    []string
+	games      []*game.Game
+	spectators []*Outbox
+	joining    []*Outbox
+}
+
+// Spectator watches games streamed by a Broadcaster, through replicas of them
+type Spectator struct {
+	conn     *Conn
+	messages chan Message
+	players  []string
+	games    []*game.Game
+	clocks   []*game.ManualClock
+	start    time.Time
+	ended    bool
+	winner   int
+}
+
+// CreateBroadcaster creates a broadcaster for some games (all created with the same settings)
+func CreateBroadcaster(config game.Config, players []string, games []*game.Game) *Broadcaster {
+	config.Clock = nil
+
+	return &Broadcaster{
+		config:  config,
+		players: players,
+		games:   games,
+	}
+}
+
+// Add adds a spectator, who gets the snapshots with the next call to SendSnapshots.
+// It is safe to call while the games are being updated.
+func (broadcaster *Broadcaster) Add(outbox *Outbox) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	broadcaster.joining = append(broadcaster.joining, outbox)
+}
+
+// SendSnapshots sends the settings and snapshots of the games to the spectators who joined since the last call.
+// It must not run while the games are being updated, and must come before the update the next message describes,
+// so the snapshots never include an update the spectators are sent afterwards.
+func (broadcaster *Broadcaster) SendSnapshots() {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	for _, outbox := range broadcaster.joining {
+		if broadcaster.sendSnapshots(outbox) {
+			broadcaster.spectators = append(broadcaster.spectators, outbox)
+		}
+	}
+
+	broadcaster.joining = nil
+}
+
+// Send sends a message to every spectator who got the snapshots, after the update the message describes
+func (broadcaster *Broadcaster) Send(message Message) {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	spectators := broadcaster.spectators[:0]
+
+	for _, outbox := range broadcaster.spectators {
+		if outbox.Send(message) {
+			spectators = append(spectators, outbox)
+		}
+	}
+
+	broadcaster.spectators = spectators
+}
+
+// sendSnapshots sends the settings and a snapshot of every game to a spectator, returning false if it can't be sent
+func (broadcaster *Broadcaster) sendSnapshots(outbox *Outbox) bool {
+	if !outbox.Send(Message{Type: MessageStart, Players: broadcaster.players, Config: &broadcaster.config}) {
+		return false
+	}
+
+	for player, current := range broadcaster.games {
+		state, err := json.Marshal(current)
+		if err != nil {
+			outbox.Close()
+			return false
+		}
+
+		if !outbox.Send(Message{Type: MessageSnapshot, Player: player, Time: current.Elapsed(), State: state}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ServeSpectators accepts spectators (who send a spectate message first) until the listener fails
+func ServeSpectators(listener net.Listener, broadcaster *Broadcaster) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			spectator := CreateConn(conn)
+			defer spectator.Close()
+
+			message, err := spectator.Receive()
+			if err != nil || message.Type != MessageSpectate {
+				return
+			}
+
+			outbox := CreateOutbox(spectator)
+			defer outbox.Close()
+
+			broadcaster.Add(outbox)
+			waitForLeave(spectator)
+		}()
+	}
+}
+
+// waitForLeave waits for a spectator to disconnect (spectators don't send anything after spectate)
+func waitForLeave(conn *Conn) {
+	var err error
+
+	for err == nil {
+		_, err = conn.Receive()
+	}
+}
+
+// Spectate connects to a server (or a game shared with -spectate) and waits for the snapshots of the games
+func Spectate(address, room string) (*Spectator, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	spectator := &Spectator{
+		conn:     CreateConn(conn),
+		messages: make(chan Message, 256),
+		start:    time.Unix(0, 0),
+		winner:   versus.NoWinner,
+	}
+
+	err = spectator.conn.Send(Message{Type: MessageSpectate, Room: room})
+	if err == nil {
+		err = spectator.receiveSnapshots()
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go spectator.receive()
+
+	return spectator, nil
+}
+
+// receiveSnapshots waits for the settings and snapshots of the games, restoring a replica of each game
+func (spectator *Spectator) receiveSnapshots() error {
+	var config *game.Config
+
+	for config == nil || len(spectator.games) < len(spectator.players) {
+		message, err := spectator.conn.Receive()
+		if err != nil {
+			return err
+		}
+
+		switch message.Type {
+		case MessageStart:
+			config = message.Config
+			spectator.players = message.Players
+			break
+		case MessageSnapshot:
+			if config == nil || message.Player != len(spectator.games) {
+				return errors.New("unexpected snapshot")
+			}
+
+			clock := game.CreateManualClock(spectator.start.Add(message.Time))
+
+			replica, err := game.RestoreGame(message.State, clock)
+			if err != nil {
+				return err
+			}
+
+			spectator.games = append(spectator.games, replica)
+			spectator.clocks = append(spectator.clocks, clock)
+			break
+		case MessageError:
+			return errors.New(message.Error)
+		}
+	}
+
+	return nil
+}
+
+// receive reads messages until the connection closes
+func (spectator *Spectator) receive() {
+	defer close(spectator.messages)
+
+	for {
+		message, err := spectator.conn.Receive()
+		if err != nil {
+			return
+		}
+
+		spectator.messages <- message
+	}
+}
+
+// Players returns the names of the players
+func (spectator *Spectator) Players() []string {
+	return spectator.players
+}
+
+// Games returns the replicas of the games, in player order
+func (spectator *Spectator) Games() []*game.Game {
+	return spectator.games
+}
+
+// Ended checks if the stream ended
+func (spectator *Spectator) Ended() bool {
+	return spectator.ended
+}
+
+// Winner returns the index of the player who won a match (versus.NoWinner if nobody did)
+func (spectator *Spectator) Winner() int {
+	return spectator.winner
+}
+
+// Update applies every message received since the last update to the replicas
+func (spectator *Spectator) Update() {
+	for {
+		select {
+		case message, ok := <-spectator.messages:
+			if !ok {
+				spectator.ended = true
+				return
+			}
+
+			spectator.handleMessage(message)
+		default:
+			return
+		}
+	}
+}
+
+// handleMessage applies a message to the replicas
+func (spectator *Spectator) handleMessage(message Message) {
+	if message.Type == MessageEnd {
+		spectator.winner = message.Winner
+		spectator.ended = true
+		return
+	}
+
+	if message.Player < 0 || message.Player >= len(spectator.games) {
+		return
+	}
+
+	replica := spectator.games[message.Player]
+
+	switch message.Type {
+	case MessageFrame:
+		spectator.clocks[message.Player].Set(spectator.start.Add(message.Time))
+		replica.Update(message.Move)
+		break
+	case MessageGarbage:
+		replica.QueueGarbage(game.Garbage{Lines: message.Lines, Hole: message.Hole})
+		break
+	}
+}
+
+// Close stops watching
+func (spectator *Spectator) Close() error {
+	return spectator.conn.Close()
+}
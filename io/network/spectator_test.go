@@ -0,0 +1,150 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+)
+
+// spectatorMoves are the moves of the shared game, which show on the board if a spectator applies one twice
+var spectatorMoves = []game.Move{
+	game.MoveLeft, game.MoveLeft, game.MoveLeft, game.HardDrop,
+	game.RotateRight, game.HardDrop,
+	game.MoveRight, game.MoveRight, game.MoveRight, game.MoveRight, game.HardDrop,
+	game.Hold, game.MoveLeft, game.HardDrop,
+}
+
+// hasJoining checks if a spectator is waiting for the snapshots
+func hasJoining(broadcaster *Broadcaster) bool {
+	broadcaster.mutex.Lock()
+	defer broadcaster.mutex.Unlock()
+
+	return len(broadcaster.joining) > 0
+}
+
+func TestSpectatorJoiningMidGame(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	clock := game.CreateManualClock(time.Unix(0, 0))
+	config := game.DefaultConfig()
+	config.Seed = 3
+	config.Clock = clock
+
+	current := game.CreateGameWithConfig(config)
+	broadcaster := CreateBroadcaster(config, []string{"player"}, []*game.Game{current})
+
+	go ServeSpectators(listener, broadcaster)
+
+	// step updates the game the way a shared game does, with some garbage between the frames
+	step := func(i int) {
+		if i%10 == 9 {
+			broadcaster.SendSnapshots()
+			current.QueueGarbage(game.Garbage{Lines: 1, Hole: i % current.Board().Width()})
+			broadcaster.Send(Message{Type: MessageGarbage, Lines: 1, Hole: i % current.Board().Width()})
+		}
+
+		move := spectatorMoves[i%len(spectatorMoves)]
+
+		broadcaster.SendSnapshots()
+		clock.Advance(time.Second / 60)
+		current.Update(move)
+		broadcaster.Send(Message{Type: MessageFrame, Time: current.Elapsed(), Move: move})
+	}
+
+	for i := 0; i < 10; i++ {
+		step(i)
+	}
+
+	joined := make(chan *Spectator, 1)
+
+	go func() {
+		spectator, err := Spectate(listener.Addr().String(), "")
+		if err != nil {
+			t.Error(err)
+		}
+
+		joined <- spectator
+	}()
+
+	// the spectator joins between two frames
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !hasJoining(broadcaster) {
+		if time.Now().After(deadline) {
+			t.Fatal("the spectator never joined")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 10; i < 30; i++ {
+		step(i)
+	}
+
+	spectator := <-joined
+	if spectator == nil {
+		t.FailNow()
+	}
+	defer spectator.Close()
+
+	if !current.IsRunning() {
+		t.Fatal("the game ended too early to test anything")
+	}
+
+	broadcaster.Send(Message{Type: MessageEnd, Winner: versus.NoWinner})
+
+	for !spectator.Ended() {
+		if time.Now().After(deadline) {
+			t.Fatal("the stream never ended")
+		}
+
+		spectator.Update()
+		time.Sleep(time.Millisecond)
+	}
+
+	replica := spectator.Games()[0]
+
+	if !replica.Board().Equal(current.Board()) || replica.Score() != current.Score() || replica.PendingGarbage() != current.PendingGarbage() {
+		t.Fatalf("the replica desynced (score %d, expected %d)", replica.Score(), current.Score())
+	}
+}
+
+func TestOutboxDropsStalledConnections(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	outbox := CreateOutbox(CreateConn(server))
+	done := make(chan bool)
+
+	// nobody reads the other end, so the outbox fills up instead of blocking the sender
+	go func() {
+		for i := 0; i <= outboxSize+1; i++ {
+			if !outbox.Send(Message{Type: MessageFrame, Move: game.NoMove}) {
+				done <- true
+				return
+			}
+		}
+
+		done <- false
+	}()
+
+	select {
+	case dropped := <-done:
+		if !dropped {
+			t.Fatal("the outbox took more messages than it holds")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("sending to a stalled connection blocked")
+	}
+
+	if outbox.Send(Message{Type: MessageFrame}) {
+		t.Fatal("a dropped outbox took a message")
+	}
+}
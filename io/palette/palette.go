@@ -0,0 +1,48 @@
+package palette
+
+import (
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+	"github.com/daplf/go-tetris/utils/consts"
+)
+
+const (
+	noColor = "No color!"
+)
+
+// ColorType is a color channel, from 0 to 1
+type ColorType = float64
+
+// ToByte converts a color channel to the 0-255 range
+func ToByte(channel ColorType) int {
+	return int(channel*255 + 0.5)
+}
+
+// GetTypeColor gets the color of a piece type
+func GetTypeColor(pieceType block.Type) (ColorType, ColorType, ColorType, consts.ErrorType) {
+	var pieceColor = []ColorType{0.0, 0.0, 0.0}
+	error := consts.NoError
+
+	switch pieceType {
+	case piece.PieceI:
+		pieceColor = PieceIColor
+	case piece.PieceJ:
+		pieceColor = PieceJColor
+	case piece.PieceL:
+		pieceColor = PieceLColor
+	case piece.PieceO:
+		pieceColor = PieceOColor
+	case piece.PieceS:
+		pieceColor = PieceSColor
+	case piece.PieceT:
+		pieceColor = PieceTColor
+	case piece.PieceZ:
+		pieceColor = PieceZColor
+	case piece.Garbage:
+		pieceColor = GarbageColor
+	default:
+		error = noColor
+	}
+
+	return pieceColor[0], pieceColor[1], pieceColor[2], error
+}
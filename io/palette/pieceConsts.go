@@ -0,0 +1,27 @@
+package palette
+
+var (
+	// PieceIColor is the color of the I piece
+	PieceIColor = []ColorType{240.0 / 255.0, 250.0 / 255.0, 50.0 / 255.0}
+
+	// PieceJColor is the color of the J piece
+	PieceJColor = []ColorType{255.0 / 255.0, 36.0 / 255.0, 36.0 / 255.0}
+
+	// PieceLColor is the color of the L piece
+	PieceLColor = []ColorType{242.0 / 255.0, 160.0 / 255.0, 49.0 / 255.0}
+
+	// PieceOColor is the color of the O piece
+	PieceOColor = []ColorType{74.0 / 255.0, 196.0 / 255.0, 217.0 / 255.0}
+
+	// PieceSColor is the color of the S piece
+	PieceSColor = []ColorType{32.0 / 255.0, 255.0 / 255.0, 82.0 / 255.0}
+
+	// PieceTColor is the color of the T piece
+	PieceTColor = []ColorType{71.0 / 255.0, 32.0 / 255.0, 255.0 / 255.0}
+
+	// PieceZColor is the color of the Z piece
+	PieceZColor = []ColorType{234.0 / 255.0, 53.0 / 255.0, 230.0 / 255.0}
+
+	// GarbageColor is the color of garbage rows
+	GarbageColor = []ColorType{128.0 / 255.0, 128.0 / 255.0, 128.0 / 255.0}
+)
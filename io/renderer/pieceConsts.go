@@ -1,11 +0,0 @@
-package renderer
-
-var (
-	pieceIColor = []colorType{240.0 / 255.0, 250.0 / 255.0, 50.0 / 255.0}
-	pieceJColor = []colorType{255.0 / 255.0, 36.0 / 255.0, 36.0 / 255.0}
-	pieceLColor = []colorType{242.0 / 255.0, 160.0 / 255.0, 49.0 / 255.0}
-	pieceOColor = []colorType{74.0 / 255.0, 196.0 / 255.0, 217.0 / 255.0}
-	pieceSColor = []colorType{32.0 / 255.0, 255.0 / 255.0, 82.0 / 255.0}
-	pieceTColor = []colorType{71.0 / 255.0, 32.0 / 255.0, 255.0 / 255.0}
-	pieceZColor = []colorType{234.0 / 255.0, 53.0 / 255.0, 230.0 / 255.0}
-)
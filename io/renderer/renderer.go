@@ -7,6 +7,8 @@ import (
 	"github.com/daplf/go-tetris/game/board"
 	"github.com/daplf/go-tetris/game/piece"
 	"github.com/daplf/go-tetris/game/piece/block"
+	"github.com/daplf/go-tetris/game/versus"
+	"github.com/daplf/go-tetris/io/palette"
 	"github.com/daplf/go-tetris/utils/consts"
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
@@ -17,19 +19,37 @@ import (
 )
 
 type windowSize = int
-type colorType = float64
 
 const (
 	windowWidthPixels  = 500
 	windowHeightPixels = 800
 	boardWidthPixels   = 400
 	boardHeightPixels  = 800
+	nextTextXPixels    = 430
+	nextTextYPixels    = 770
+	nextPieceXPixels   = 455
+	nextPieceYPixels   = 730
+	nextPieceGapPixels = 45
+	maxNextPieces      = 5
 	scoreTextXPixels   = 430
-	scoreTextYPixels   = 760
+	scoreTextYPixels   = 480
+	holdTextXPixels    = 430
+	holdTextYPixels    = 420
+	holdPieceXPixels   = 455
+	holdPieceYPixels   = 380
+	levelTextXPixels   = 420
+	levelTextYPixels   = 320
 	pausedTextXPixels  = 430
-	pausedTextYPixels  = 560
+	pausedTextYPixels  = 240
+	promptTextXPixels  = 40
+	garbageMeterPixels = 20
+	promptTextYPixels  = 420
+	previewBlockPixels = 15
+	ghostLinePixels    = 2
 	windowTitle        = "Tetris"
-	noColor            = "No color!"
+	pausedStatus       = "Paused"
+	winnerStatus       = "Winner"
+	loserStatus        = "Game over"
 )
 
 // Renderer holds rendering logic
@@ -37,6 +57,10 @@ type Renderer struct {
 	window     *pixelgl.Window
 	scoreText  *text.Text
 	pausedText *text.Text
+	holdText   *text.Text
+	nextText   *text.Text
+	levelText  *text.Text
+	promptText *text.Text
 }
 
 // Window returns the window
@@ -46,22 +70,40 @@ func (renderer *Renderer) Window() *pixelgl.Window {
 
 // CreateRenderer creates a new renderer with default dimensions
 func CreateRenderer() *Renderer {
-	window := setupWindow()
+	return createRenderer(windowWidthPixels)
+}
+
+// CreateVersusRenderer creates a renderer with room for two boards and their garbage meters
+func CreateVersusRenderer() *Renderer {
+	return createRenderer(versus.Players * (garbageMeterPixels + windowWidthPixels))
+}
+
+// createRenderer creates a new renderer with a window of the given width
+func createRenderer(width windowSize) *Renderer {
+	window := setupWindow(width)
 	scoreText := setupScoreText()
 	pausedText := setupPausedText()
+	holdText := setupHoldText()
+	nextText := setupNextText()
+	levelText := setupLevelText()
+	promptText := setupPromptText()
 
 	return &Renderer{
 		window:     window,
 		scoreText:  scoreText,
 		pausedText: pausedText,
+		holdText:   holdText,
+		nextText:   nextText,
+		levelText:  levelText,
+		promptText: promptText,
 	}
 }
 
 // setupWindow sets up the OpenGL context and window
-func setupWindow() *pixelgl.Window {
+func setupWindow(width windowSize) *pixelgl.Window {
 	cfg := pixelgl.WindowConfig{
 		Title:  windowTitle,
-		Bounds: pixel.R(0, 0, windowWidthPixels, windowHeightPixels),
+		Bounds: pixel.R(0, 0, float64(width), windowHeightPixels),
 		VSync:  true,
 	}
 
@@ -99,36 +141,168 @@ func setupPausedText() *text.Text {
 	return pausedText
 }
 
-// DrawBoard draws the board on the screen
-func (renderer *Renderer) DrawBoard(game *game.Game) {
+// setupHoldText sets up the text used for the held piece label.
+func setupHoldText() *text.Text {
+	holdAtlas := text.NewAtlas(
+		basicfont.Face7x13,
+		text.ASCII,
+	)
+
+	holdText := text.New(pixel.V(holdTextXPixels, holdTextYPixels), holdAtlas)
+	holdText.Color = colornames.Yellow
+
+	return holdText
+}
+
+// setupNextText sets up the text used for the next pieces label.
+func setupNextText() *text.Text {
+	nextAtlas := text.NewAtlas(
+		basicfont.Face7x13,
+		text.ASCII,
+	)
+
+	nextText := text.New(pixel.V(nextTextXPixels, nextTextYPixels), nextAtlas)
+	nextText.Color = colornames.Yellow
+
+	return nextText
+}
+
+// setupLevelText sets up the text used for the level and cleared lines.
+func setupLevelText() *text.Text {
+	levelAtlas := text.NewAtlas(
+		basicfont.Face7x13,
+		text.ASCII,
+	)
+
+	levelText := text.New(pixel.V(levelTextXPixels, levelTextYPixels), levelAtlas)
+	levelText.Color = colornames.Yellow
+
+	return levelText
+}
+
+// setupPromptText sets up the text used for questions shown outside of the game.
+func setupPromptText() *text.Text {
+	promptAtlas := text.NewAtlas(
+		basicfont.Face7x13,
+		text.ASCII,
+	)
+
+	promptText := text.New(pixel.V(promptTextXPixels, promptTextYPixels), promptAtlas)
+	promptText.Color = colornames.Yellow
+
+	return promptText
+}
+
+// DrawPrompt draws a question on an otherwise empty screen
+func (renderer *Renderer) DrawPrompt(message string) {
 	renderer.window.Clear(colornames.Black)
 
-	squares := game.Board().Squares()
-	width := game.Board().Width()
-	height := game.Board().Height()
+	renderer.promptText.Clear()
+	fmt.Fprintln(renderer.promptText, message)
+	renderer.promptText.Draw(renderer.window, pixel.IM.Scaled(renderer.promptText.Orig, 2))
 
-	for _, row := range squares {
-		for _, block := range row {
-			if block != nil {
-				renderer.drawBlock(block, width, height)
-			}
+	renderer.window.Update()
+}
+
+// DrawFrame draws a snapshot of a game on the screen
+func (renderer *Renderer) DrawFrame(snapshot game.Snapshot) {
+	renderer.window.Clear(colornames.Black)
+
+	renderer.drawSnapshot(snapshot, pausedStatusText(snapshot))
+
+	renderer.window.Update()
+}
+
+// DrawVersus draws the snapshots of both players of a versus game side by side, each with an incoming garbage meter
+func (renderer *Renderer) DrawVersus(snapshots [versus.Players]game.Snapshot, winner int) {
+	renderer.window.Clear(colornames.Black)
+
+	for player, snapshot := range snapshots {
+		offset := float64(player * (garbageMeterPixels + windowWidthPixels))
+		status := pausedStatusText(snapshot)
+
+		if winner == player {
+			status = winnerStatus
+		} else if winner != versus.NoWinner {
+			status = loserStatus
 		}
+
+		renderer.window.SetMatrix(pixel.IM.Moved(pixel.V(offset, 0)))
+		renderer.drawGarbageMeter(snapshot)
+
+		renderer.window.SetMatrix(pixel.IM.Moved(pixel.V(offset+garbageMeterPixels, 0)))
+		renderer.drawSnapshot(snapshot, status)
 	}
 
-	renderer.drawInfoTab(game)
+	renderer.window.SetMatrix(pixel.IM)
 
 	renderer.window.Update()
 }
 
-// drawBlock draws a block on the screen
-func (renderer *Renderer) drawBlock(block *block.Block, boardWidth, boardHeight board.Size) {
-	r, g, b, error := getBlockColor(block)
+// pausedStatusText returns the status shown for a game that may be paused
+func pausedStatusText(snapshot game.Snapshot) string {
+	if snapshot.Paused {
+		return pausedStatus
+	}
+
+	return ""
+}
+
+// drawGarbageMeter draws a bar as high as the garbage rows waiting to be inserted
+func (renderer *Renderer) drawGarbageMeter(snapshot game.Snapshot) {
+	if snapshot.Height == 0 {
+		return
+	}
+
+	height := float64(snapshot.PendingGarbage * boardHeightPixels / snapshot.Height)
+	if height > boardHeightPixels {
+		height = boardHeightPixels
+	}
+
+	if height > 0 {
+		drawPolygon(
+			renderer.window,
+			pixel.RGB(1, 0, 0),
+			[][2]float64{
+				{0, 0},
+				{garbageMeterPixels, 0},
+				{garbageMeterPixels, height},
+				{0, height},
+			},
+		)
+	}
+}
+
+// drawSnapshot draws the board and info tab of a game, with a status shown in the info tab
+func (renderer *Renderer) drawSnapshot(snapshot game.Snapshot, status string) {
+	for _, ghost := range snapshot.Ghost {
+		renderer.drawGhostSquare(ghost, snapshot.Width, snapshot.Height)
+	}
+
+	for _, hint := range snapshot.Hint {
+		renderer.drawSquareOutline(hint, pixel.RGB(1, 1, 1), snapshot.Width, snapshot.Height)
+	}
+
+	for y, row := range snapshot.Squares {
+		for x, squareType := range row {
+			if squareType != piece.NoPiece {
+				renderer.drawSquare(game.Square{X: x, Y: y, Type: squareType}, snapshot.Width, snapshot.Height)
+			}
+		}
+	}
+
+	renderer.drawInfoTab(snapshot, status)
+}
+
+// drawSquare draws a square of the board on the screen
+func (renderer *Renderer) drawSquare(square game.Square, boardWidth, boardHeight board.Size) {
+	r, g, b, error := palette.GetTypeColor(square.Type)
 
 	if error == consts.NoError {
 		blockWidth := float64(boardWidthPixels / boardWidth)
 		blockHeight := float64(boardHeightPixels / boardHeight)
-		x1 := float64(block.X()) * blockWidth
-		y1 := float64(block.Y()) * blockHeight
+		x1 := float64(square.X) * blockWidth
+		y1 := float64(square.Y) * blockHeight
 		x2 := x1 + blockWidth
 		y2 := y1 + blockHeight
 
@@ -145,62 +319,119 @@ func (renderer *Renderer) drawBlock(block *block.Block, boardWidth, boardHeight
 	}
 }
 
-// drawScore draws the score on the screen.
-func (renderer *Renderer) drawInfoTab(game *game.Game) {
+// drawGhostSquare draws the outline of a square of the board on the screen
+func (renderer *Renderer) drawGhostSquare(square game.Square, boardWidth, boardHeight board.Size) {
+	r, g, b, error := palette.GetTypeColor(square.Type)
+
+	if error == consts.NoError {
+		renderer.drawSquareOutline(square, pixel.RGB(r, g, b), boardWidth, boardHeight)
+	}
+}
+
+// drawSquareOutline draws the outline of a square of the board on the screen in some color
+func (renderer *Renderer) drawSquareOutline(square game.Square, color pixel.RGBA, boardWidth, boardHeight board.Size) {
+	blockWidth := float64(boardWidthPixels / boardWidth)
+	blockHeight := float64(boardHeightPixels / boardHeight)
+	x1 := float64(square.X)*blockWidth + ghostLinePixels/2
+	y1 := float64(square.Y)*blockHeight + ghostLinePixels/2
+	x2 := x1 + blockWidth - ghostLinePixels
+	y2 := y1 + blockHeight - ghostLinePixels
+
+	drawPolygonOutline(
+		renderer.window,
+		color,
+		[][2]float64{
+			{x1, y1},
+			{x2, y1},
+			{x2, y2},
+			{x1, y2},
+		},
+		ghostLinePixels,
+	)
+}
+
+// drawInfoTab draws the panel next to the board: the next pieces, score, level, lines, status and held piece
+func (renderer *Renderer) drawInfoTab(snapshot game.Snapshot, status string) {
 	drawPolygon(
 		renderer.window,
 		pixel.RGB(0, 0, 1),
 		[][2]float64{
 			{boardWidthPixels, 0},
-			{windowHeightPixels, 0},
-			{windowHeightPixels, windowHeightPixels},
+			{windowWidthPixels, 0},
+			{windowWidthPixels, windowHeightPixels},
 			{boardWidthPixels, windowHeightPixels},
 		},
 	)
 
+	renderer.nextText.Clear()
+	fmt.Fprintln(renderer.nextText, "Next")
+	renderer.nextText.Draw(renderer.window, pixel.IM.Scaled(renderer.nextText.Orig, 1.5))
+
+	for i, pieceType := range snapshot.Queue {
+		if i >= maxNextPieces {
+			break
+		}
+
+		renderer.drawPieceType(pieceType, snapshot.RotationSystem, nextPieceXPixels, float64(nextPieceYPixels-i*nextPieceGapPixels))
+	}
+
 	renderer.scoreText.Clear()
-	fmt.Fprintln(renderer.scoreText, game.Score())
+	fmt.Fprintln(renderer.scoreText, snapshot.Score)
 	renderer.scoreText.Draw(renderer.window, pixel.IM.Scaled(renderer.scoreText.Orig, 2))
 
-	pausedText := ""
-	if game.IsPaused() {
-		pausedText = "Paused"
-	}
+	renderer.levelText.Clear()
+	fmt.Fprintf(renderer.levelText, "Level %d\nLines %d\n", snapshot.Level, snapshot.Lines)
+	renderer.levelText.Draw(renderer.window, pixel.IM.Scaled(renderer.levelText.Orig, 1.2))
 
 	renderer.pausedText.Clear()
-	fmt.Fprintln(renderer.pausedText, pausedText)
+	fmt.Fprintln(renderer.pausedText, status)
 	renderer.pausedText.Draw(renderer.window, pixel.IM.Scaled(renderer.pausedText.Orig, 1.5))
-}
 
-// getBlockColor gets a block's color
-func getBlockColor(block *block.Block) (colorType, colorType, colorType, consts.ErrorType) {
-	var pieceColor = []colorType{0.0, 0.0, 0.0}
-	error := consts.NoError
+	renderer.holdText.Clear()
+	fmt.Fprintln(renderer.holdText, "Hold")
+	renderer.holdText.Draw(renderer.window, pixel.IM.Scaled(renderer.holdText.Orig, 1.5))
+
+	renderer.drawPieceType(snapshot.Held, snapshot.RotationSystem, holdPieceXPixels, holdPieceYPixels)
+}
 
-	switch block.Type() {
-	case piece.PieceI:
-		pieceColor = pieceIColor
-	case piece.PieceJ:
-		pieceColor = pieceJColor
-	case piece.PieceL:
-		pieceColor = pieceLColor
-	case piece.PieceO:
-		pieceColor = pieceOColor
-	case piece.PieceS:
-		pieceColor = pieceSColor
-	case piece.PieceT:
-		pieceColor = pieceTColor
-	case piece.PieceZ:
-		pieceColor = pieceZColor
-	default:
-		error = noColor
+// drawPieceType draws a piece of the given type in its spawn state at the given position (nothing without a rotation system)
+func (renderer *Renderer) drawPieceType(pieceType block.Type, rotationSystem piece.RotationSystem, x, y float64) {
+	if rotationSystem == nil {
+		return
 	}
 
-	return pieceColor[0], pieceColor[1], pieceColor[2], error
+	r, g, b, error := palette.GetTypeColor(pieceType)
+
+	if error == consts.NoError {
+		coords := rotationSystem.Coords(pieceType, rotationSystem.SpawnState(pieceType))
+
+		for i := range coords[0] {
+			x1 := x + float64(coords[0][i])*previewBlockPixels
+			y1 := y + float64(coords[1][i])*previewBlockPixels
+			x2 := x1 + previewBlockPixels
+			y2 := y1 + previewBlockPixels
+
+			drawPolygon(
+				renderer.window,
+				pixel.RGB(r, g, b),
+				[][2]float64{
+					{x1, y1},
+					{x2, y1},
+					{x2, y2},
+					{x1, y2},
+				},
+			)
+		}
+	}
 }
 
 // drawPolygon draws a poligon on the given target
 func drawPolygon(target pixel.Target, color pixel.RGBA, vertices [][2]float64) {
+	drawPolygonOutline(target, color, vertices, 0)
+}
+
+// drawPolygonOutline draws the outline of a poligon on the given target (filled if thickness is 0)
+func drawPolygonOutline(target pixel.Target, color pixel.RGBA, vertices [][2]float64, thickness float64) {
 	imd := imdraw.New(nil)
 
 	imd.Color = color
@@ -209,7 +440,7 @@ func drawPolygon(target pixel.Target, color pixel.RGBA, vertices [][2]float64) {
 		imd.Push(pixel.V(row[0], row[1]))
 	}
 
-	imd.Polygon(0)
+	imd.Polygon(thickness)
 
 	imd.Draw(target)
 }
@@ -0,0 +1,219 @@
+package tbp
+
+import (
+	"errors"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/placement"
+)
+
+// Player plays games with the placements a bot suggests, making the moves that reach them one update at a time
+type Player struct {
+	bot          *Bot
+	game         *game.Game
+	subscription int
+	plan         *placement.Plan
+	hold         bool
+	started      bool
+	restart      bool
+	played       int
+	err          error
+}
+
+// CreatePlayer creates a player for a bot (one game at a time, but games can follow each other)
+func CreatePlayer(bot *Bot) *Player {
+	return &Player{
+		bot: bot,
+	}
+}
+
+// Err returns the error that stopped the player, if any
+func (player *Player) Err() error {
+	return player.err
+}
+
+// Input returns the move to make on the next update of a game (it can be used as a headless.Input)
+func (player *Player) Input(current *game.Game) game.Move {
+	if player.err != nil || !current.IsRunning() || current.IsPaused() || current.CurrentPiece() == nil {
+		return game.NoMove
+	}
+
+	if current != player.game {
+		player.err = player.attach(current)
+	}
+
+	if player.err == nil && player.plan == nil {
+		player.err = player.choose(current)
+	}
+
+	if player.err != nil {
+		return game.NoMove
+	}
+
+	if player.hold {
+		player.hold = false
+		return game.Hold
+	}
+
+	move, ok := player.plan.NextMove(current)
+	if !ok {
+		// the bot's placement can't be reached anymore, so drop the piece and show the bot the real board afterwards
+		player.restart = true
+		return game.HardDrop
+	}
+
+	return move
+}
+
+// attach starts following a new game
+func (player *Player) attach(current *game.Game) error {
+	if current.Board().Width() != BoardWidth || current.Board().Height() > BoardHeight {
+		return errors.New("bots only play on boards 10 columns wide and up to 40 rows high")
+	}
+
+	if player.game != nil {
+		player.game.Unsubscribe(player.subscription)
+	}
+
+	player.game = current
+	player.subscription = current.Subscribe(player.handleEvent)
+	player.plan = nil
+	player.hold = false
+	player.restart = true
+
+	return nil
+}
+
+// handleEvent waits for the next piece once one is locked, and notices when the bot's board gets out of date
+func (player *Player) handleEvent(event game.Event) {
+	switch event.(type) {
+	case game.PieceLockedEvent:
+		player.plan = nil
+		break
+	case game.GarbageInsertedEvent:
+		player.restart = true
+		break
+	}
+}
+
+// choose asks the bot where to place the current piece, and plans the moves to get there
+func (player *Player) choose(current *game.Game) error {
+	err := player.update(current)
+	if err != nil {
+		return err
+	}
+
+	moves, err := player.bot.Suggest()
+	if err != nil {
+		return err
+	}
+
+	currentType := current.CurrentPiece().Type()
+	holdType := current.HeldPiece()
+
+	if holdType == piece.NoPiece && len(current.Queue()) > 0 {
+		holdType = current.Queue()[0]
+	}
+
+	placements := placement.Find(current)
+
+	var heldPlacements []placement.Placement
+	held := false
+
+	for _, move := range moves {
+		target, ok := move.Placement()
+		if !ok {
+			continue
+		}
+
+		candidates := placements
+
+		if target.Type != currentType {
+			if target.Type != holdType {
+				continue
+			}
+
+			if !held {
+				heldPlacements = findHeldPlacements(current)
+				held = true
+			}
+
+			candidates = heldPlacements
+		}
+
+		for _, reachable := range candidates {
+			if !reachable.Matches(target) {
+				continue
+			}
+
+			player.plan = placement.CreatePlan(reachable)
+			player.played = 1
+
+			if target.Type != currentType {
+				player.hold = true
+
+				if current.HeldPiece() == piece.NoPiece {
+					player.played = 2
+				}
+			}
+
+			return player.bot.Play(move)
+		}
+	}
+
+	if len(placements) == 0 {
+		return errors.New("the current piece has nowhere to go")
+	}
+
+	// none of the suggestions can be reached, so the piece goes somewhere else and the bot starts over
+	player.plan = placement.CreatePlan(placements[0])
+	player.restart = true
+
+	return nil
+}
+
+// findHeldPlacements returns the placements the piece coming out of the hold could reach,
+// by holding the current piece in a copy of the game (none if the piece can't be held)
+func findHeldPlacements(current *game.Game) []placement.Placement {
+	copied := current.Copy()
+	copied.Update(game.Hold)
+
+	if copied.HeldPiece() != current.CurrentPiece().Type() {
+		return nil
+	}
+
+	return placement.Find(copied)
+}
+
+// update brings the bot up to date: it restarts the bot on the game's state if the bot's board is out of date,
+// and otherwise tells it about the pieces added to the queue since its last placement
+func (player *Player) update(current *game.Game) error {
+	queue := current.Queue()
+
+	if player.restart || player.played > len(queue) {
+		if player.started {
+			err := player.bot.Stop()
+			if err != nil {
+				return err
+			}
+		}
+
+		player.restart = false
+		player.started = true
+		player.played = 0
+
+		return player.bot.Start(CreateState(current))
+	}
+
+	for _, pieceType := range queue[len(queue)-player.played:] {
+		err := player.bot.NewPiece(pieceType)
+		if err != nil {
+			return err
+		}
+	}
+
+	player.played = 0
+
+	return nil
+}
@@ -0,0 +1,366 @@
+package tbp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+	"github.com/daplf/go-tetris/game/placement"
+)
+
+const (
+	// BoardWidth is the width of the boards bots play on
+	BoardWidth = 10
+
+	// BoardHeight is the number of rows sent to bots (rows above the game's board are empty)
+	BoardHeight = 40
+
+	// SpinNone is a placement that isn't a spin
+	SpinNone = "none"
+
+	// SpinMini is a T-spin mini
+	SpinMini = "mini"
+
+	// SpinFull is a full T-spin
+	SpinFull = "full"
+
+	messageInfo       = "info"
+	messageError      = "error"
+	messageReady      = "ready"
+	messageSuggestion = "suggestion"
+	messageRules      = "rules"
+	messageStart      = "start"
+	messageStop       = "stop"
+	messageSuggest    = "suggest"
+	messagePlay       = "play"
+	messageNewPiece   = "new_piece"
+	messageQuit       = "quit"
+
+	maxMessageSize = 1 << 20
+)
+
+var (
+	// pieceLetters maps the piece types to the letters used by the protocol
+	pieceLetters = map[block.Type]string{
+		piece.PieceI:  "I",
+		piece.PieceJ:  "J",
+		piece.PieceL:  "L",
+		piece.PieceO:  "O",
+		piece.PieceS:  "S",
+		piece.PieceT:  "T",
+		piece.PieceZ:  "Z",
+		piece.Garbage: "G",
+	}
+
+	// orientations holds the orientations of the protocol, in the order of the piece states
+	orientations = []string{"north", "east", "south", "west"}
+
+	// northCells holds the cells of each piece pointing north, relative to the piece's center
+	northCells = map[string][][2]block.Position{
+		"I": {{-1, 0}, {0, 0}, {1, 0}, {2, 0}},
+		"J": {{-1, 0}, {0, 0}, {1, 0}, {-1, 1}},
+		"L": {{-1, 0}, {0, 0}, {1, 0}, {1, 1}},
+		"O": {{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+		"S": {{-1, 0}, {0, 0}, {0, 1}, {1, 1}},
+		"T": {{-1, 0}, {0, 0}, {1, 0}, {0, 1}},
+		"Z": {{-1, 1}, {0, 1}, {0, 0}, {1, 0}},
+	}
+)
+
+// Info describes a bot, as it introduced itself
+type Info struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Author   string   `json:"author"`
+	Features []string `json:"features"`
+}
+
+// Location is where a piece is: the position of its center and its orientation (north, east, south or west)
+type Location struct {
+	Type        string         `json:"type"`
+	Orientation string         `json:"orientation"`
+	X           block.Position `json:"x"`
+	Y           block.Position `json:"y"`
+}
+
+// Move is a placement suggested by a bot (Spin is SpinNone, SpinMini or SpinFull)
+type Move struct {
+	Location Location `json:"location"`
+	Spin     string   `json:"spin"`
+}
+
+// State is the state of a game sent to a bot when it starts.
+// Queue starts with the current piece, and Board holds BoardHeight rows (bottom first) of BoardWidth cells
+// (nil if empty, a piece letter otherwise).
+type State struct {
+	Hold       *string     `json:"hold"`
+	Queue      []string    `json:"queue"`
+	Combo      int         `json:"combo"`
+	BackToBack bool        `json:"back_to_back"`
+	Board      [][]*string `json:"board"`
+}
+
+// Bot is an external AI speaking the Tetris Bot Protocol over the standard input and output of a child process
+type Bot struct {
+	command *exec.Cmd
+	input   io.WriteCloser
+	encoder *json.Encoder
+	scanner *bufio.Scanner
+	info    Info
+}
+
+// message is a message of the protocol; only the fields of its type are set
+// (Info for info, Reason for error, Moves for suggestion, State for start, Move for play and Piece for new_piece)
+type message struct {
+	Type string `json:"type"`
+	*Info
+	Reason string `json:"reason,omitempty"`
+	Moves  []Move `json:"moves,omitempty"`
+	*State
+	Move  *Move  `json:"move,omitempty"`
+	Piece string `json:"piece,omitempty"`
+}
+
+// CreateBot starts a bot (a command that wasn't started yet) and waits until it is ready to play
+func CreateBot(command *exec.Cmd) (*Bot, error) {
+	input, err := command.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := command.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = command.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(make([]byte, 4096), maxMessageSize)
+
+	bot := &Bot{
+		command: command,
+		input:   input,
+		encoder: json.NewEncoder(input),
+		scanner: scanner,
+	}
+
+	err = bot.handshake()
+	if err != nil {
+		bot.Close()
+		return nil, err
+	}
+
+	return bot, nil
+}
+
+// handshake reads the bot's info, sends the rules and waits for the bot to accept them
+func (bot *Bot) handshake() error {
+	info, err := bot.receive(messageInfo)
+	if err != nil {
+		return err
+	}
+
+	if info.Info != nil {
+		bot.info = *info.Info
+	}
+
+	err = bot.send(message{Type: messageRules})
+	if err != nil {
+		return err
+	}
+
+	_, err = bot.receive(messageReady)
+
+	return err
+}
+
+// Info returns what the bot told about itself
+func (bot *Bot) Info() Info {
+	return bot.info
+}
+
+// Start makes the bot start thinking about a game
+func (bot *Bot) Start(state State) error {
+	return bot.send(message{Type: messageStart, State: &state})
+}
+
+// Stop makes the bot forget the game it was thinking about
+func (bot *Bot) Stop() error {
+	return bot.send(message{Type: messageStop})
+}
+
+// Suggest asks the bot where to place the current piece, returning its suggestions (best first)
+func (bot *Bot) Suggest() ([]Move, error) {
+	err := bot.send(message{Type: messageSuggest})
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion, err := bot.receive(messageSuggestion)
+
+	return suggestion.Moves, err
+}
+
+// Play tells the bot where the current piece was placed
+func (bot *Bot) Play(move Move) error {
+	return bot.send(message{Type: messagePlay, Move: &move})
+}
+
+// NewPiece tells the bot a piece was added at the end of the queue
+func (bot *Bot) NewPiece(pieceType block.Type) error {
+	return bot.send(message{Type: messageNewPiece, Piece: pieceLetters[pieceType]})
+}
+
+// Close asks the bot to quit and waits for it to exit
+func (bot *Bot) Close() error {
+	bot.send(message{Type: messageQuit})
+	bot.input.Close()
+
+	return bot.command.Wait()
+}
+
+// send writes a message to the bot
+func (bot *Bot) send(message message) error {
+	return bot.encoder.Encode(message)
+}
+
+// receive waits for a message of a given type, failing if the bot reports an error instead
+func (bot *Bot) receive(messageType string) (message, error) {
+	received := message{}
+
+	if !bot.scanner.Scan() {
+		err := bot.scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+
+		return received, err
+	}
+
+	err := json.Unmarshal(bot.scanner.Bytes(), &received)
+	if err != nil {
+		return received, err
+	}
+
+	if received.Type == messageError {
+		return received, errors.New("bot error: " + received.Reason)
+	}
+
+	if received.Type != messageType {
+		return received, fmt.Errorf("expected a %s message from the bot, got %s", messageType, received.Type)
+	}
+
+	return received, nil
+}
+
+// CreateState converts the state of a game (which must be BoardWidth columns wide) to the state sent to a bot
+func CreateState(current *game.Game) State {
+	state := State{
+		Queue:      []string{},
+		Combo:      current.Combo() + 1,
+		BackToBack: current.BackToBack(),
+		Board:      make([][]*string, BoardHeight),
+	}
+
+	if current.HeldPiece() != piece.NoPiece {
+		letter := pieceLetters[current.HeldPiece()]
+		state.Hold = &letter
+	}
+
+	if current.CurrentPiece() != nil {
+		state.Queue = append(state.Queue, pieceLetters[current.CurrentPiece().Type()])
+	}
+
+	for _, pieceType := range current.Queue() {
+		state.Queue = append(state.Queue, pieceLetters[pieceType])
+	}
+
+	isCurrentPiece := func(square *block.Block) bool {
+		if current.CurrentPiece() == nil {
+			return false
+		}
+
+		for _, pieceBlock := range current.CurrentPiece().Blocks() {
+			if square == pieceBlock {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	squares := current.Board().Squares()
+
+	for y := range state.Board {
+		state.Board[y] = make([]*string, BoardWidth)
+
+		if y >= len(squares) {
+			continue
+		}
+
+		for x, square := range squares[y] {
+			if square != nil && !isCurrentPiece(square) {
+				letter := pieceLetters[square.Type()]
+				state.Board[y][x] = &letter
+			}
+		}
+	}
+
+	return state
+}
+
+// Placement converts a move to the placement of its piece, or returns false if the move isn't valid
+func (move Move) Placement() (placement.Placement, bool) {
+	cells, ok := northCells[move.Location.Type]
+	if !ok {
+		return placement.Placement{}, false
+	}
+
+	state := piece.NormalState
+	for state < len(orientations) && orientations[state] != move.Location.Orientation {
+		state++
+	}
+
+	if state == len(orientations) {
+		return placement.Placement{}, false
+	}
+
+	pieceType := block.Type("")
+	for candidate, letter := range pieceLetters {
+		if letter == move.Location.Type {
+			pieceType = candidate
+		}
+	}
+
+	squares := make([]game.Square, 0, len(cells))
+
+	for _, cell := range cells {
+		x, y := cell[0], cell[1]
+
+		// each orientation is a quarter turn clockwise from the previous one
+		for turn := 0; turn < state; turn++ {
+			x, y = y, -x
+		}
+
+		squares = append(squares, game.Square{X: move.Location.X + x, Y: move.Location.Y + y, Type: pieceType})
+	}
+
+	placement.SortSquares(squares)
+
+	return placement.Placement{
+		Type:    pieceType,
+		State:   state,
+		Squares: squares,
+		Rotated: (move.Spin == SpinMini || move.Spin == SpinFull) && pieceType == piece.PieceT,
+	}, true
+}
@@ -0,0 +1,191 @@
+package tbp
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/placement"
+)
+
+func TestMovePlacement(t *testing.T) {
+	tests := []struct {
+		name    string
+		move    Move
+		ok      bool
+		squares []game.Square
+		rotated bool
+	}{
+		{
+			"T north",
+			Move{Location: Location{Type: "T", Orientation: "north", X: 4, Y: 0}, Spin: SpinNone},
+			true,
+			[]game.Square{{X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 4, Y: 1}},
+			false,
+		},
+		{
+			"T south spin",
+			Move{Location: Location{Type: "T", Orientation: "south", X: 4, Y: 1}, Spin: SpinFull},
+			true,
+			[]game.Square{{X: 4, Y: 0}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 5, Y: 1}},
+			true,
+		},
+		{
+			"I east",
+			Move{Location: Location{Type: "I", Orientation: "east", X: 0, Y: 2}, Spin: SpinNone},
+			true,
+			[]game.Square{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 0, Y: 3}},
+			false,
+		},
+		{
+			"spin on another piece",
+			Move{Location: Location{Type: "J", Orientation: "west", X: 5, Y: 1}, Spin: SpinMini},
+			true,
+			[]game.Square{{X: 5, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 1}, {X: 5, Y: 2}},
+			false,
+		},
+		{"unknown piece", Move{Location: Location{Type: "X", Orientation: "north"}}, false, nil, false},
+		{"unknown orientation", Move{Location: Location{Type: "T", Orientation: "up"}}, false, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target, ok := test.move.Placement()
+			if ok != test.ok {
+				t.Fatalf("got ok %t, expected %t", ok, test.ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			expected := placement.Placement{Squares: append([]game.Square{}, test.squares...), Rotated: test.rotated}
+
+			for i := range expected.Squares {
+				expected.Squares[i].Type = target.Type
+			}
+
+			placement.SortSquares(expected.Squares)
+
+			if !target.Matches(expected) {
+				t.Fatalf("got %v (rotated %t), expected %v (rotated %t)", target.Squares, target.Rotated, test.squares, test.rotated)
+			}
+		})
+	}
+}
+
+func TestCreateState(t *testing.T) {
+	config := game.DefaultConfig()
+	config.Seed = 1
+	config.Clock = game.CreateManualClock(time.Unix(0, 0))
+
+	current := game.CreateGameWithConfig(config)
+	currentType := current.CurrentPiece().Type()
+	current.Update(game.HardDrop)
+	current.Update(game.Hold)
+
+	state := CreateState(current)
+
+	if state.Hold == nil || *state.Hold != pieceLetters[current.HeldPiece()] {
+		t.Fatalf("got hold %v, expected %s", state.Hold, pieceLetters[current.HeldPiece()])
+	}
+
+	if len(state.Queue) != len(current.Queue())+1 || state.Queue[0] != pieceLetters[current.CurrentPiece().Type()] {
+		t.Fatalf("got queue %v, which should start with the current piece", state.Queue)
+	}
+
+	if len(state.Board) != BoardHeight || len(state.Board[0]) != BoardWidth {
+		t.Fatalf("got a %dx%d board", len(state.Board[0]), len(state.Board))
+	}
+
+	cells := 0
+
+	for _, row := range state.Board {
+		for _, cell := range row {
+			if cell != nil {
+				cells++
+
+				if *cell != pieceLetters[currentType] {
+					t.Fatalf("got cell %s, expected %s", *cell, pieceLetters[currentType])
+				}
+			}
+		}
+	}
+
+	// only the dropped piece is on the board, not the current one
+	if cells != 4 {
+		t.Fatalf("got %d cells, expected 4", cells)
+	}
+}
+
+func TestReceive(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		moves  int
+		failed bool
+	}{
+		{"suggestion", `{"type":"suggestion","moves":[{"location":{"type":"T","orientation":"north","x":4,"y":0},"spin":"none"}]}`, 1, false},
+		{"error", `{"type":"error","reason":"unsupported rules"}`, 0, true},
+		{"other message", `{"type":"ready"}`, 0, true},
+		{"invalid JSON", `{"type":`, 0, true},
+		{"nothing", ``, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bot := &Bot{scanner: bufio.NewScanner(strings.NewReader(test.input + "\n"))}
+
+			received, err := bot.receive(messageSuggestion)
+			if (err != nil) != test.failed {
+				t.Fatalf("got error %v, expected failure to be %t", err, test.failed)
+			}
+
+			if len(received.Moves) != test.moves {
+				t.Fatalf("got %d moves, expected %d", len(received.Moves), test.moves)
+			}
+		})
+	}
+}
+
+func TestPieceLettersCoverEveryPiece(t *testing.T) {
+	for _, pieceType := range piece.Types {
+		if _, ok := northCells[pieceLetters[pieceType]]; !ok {
+			t.Fatalf("%s has no cells", pieceType)
+		}
+	}
+}
+
+func TestFindHeldPlacements(t *testing.T) {
+	config := game.DefaultConfig()
+	config.Seed = 2
+	config.Clock = game.CreateManualClock(time.Unix(0, 0))
+
+	current := game.CreateGameWithConfig(config)
+	next := current.Queue()[0]
+
+	placements := findHeldPlacements(current)
+	if len(placements) == 0 {
+		t.Fatal("the piece coming out of the hold has nowhere to go")
+	}
+
+	for _, reachable := range placements {
+		if reachable.Type != next {
+			t.Fatalf("got a placement for %s, expected %s", reachable.Type, next)
+		}
+	}
+
+	if current.HeldPiece() != piece.NoPiece || current.Queue()[0] != next {
+		t.Fatal("finding the held placements changed the game")
+	}
+
+	// a piece that came out of the hold can't be held again
+	current.Update(game.Hold)
+
+	if placements := findHeldPlacements(current); placements != nil {
+		t.Fatalf("got %d placements for a piece that can't be held", len(placements))
+	}
+}
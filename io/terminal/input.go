@@ -0,0 +1,237 @@
+package terminal
+
+import (
+	"os"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+const (
+	// frameDuration is how long GetInput waits for a key, which paces the game loop
+	frameDuration = time.Second / 60
+
+	// softDropHold is how long a soft drop lasts after the down key, as terminals only report key presses
+	// (the key repeat of a held key keeps it going)
+	softDropHold = 150 * time.Millisecond
+
+	// escapeTimeout is how long the rest of an escape sequence is waited for before the escape key is taken alone
+	escapeTimeout = 50 * time.Millisecond
+
+	keyInterrupt = 0x03
+	keyEscape    = 0x1b
+	keyCSI       = '['
+	keyFinalMin  = 0x40
+	keyFinalMax  = 0x7e
+	keyUp        = 'A'
+	keyDown      = 'B'
+	keyRight     = 'C'
+	keyLeft      = 'D'
+)
+
+var keyMoves = map[byte]game.Move{
+	' ':          game.HardDrop,
+	'a':          game.RotateLeft,
+	's':          game.RotateLeft,
+	'z':          game.RotateLeft,
+	'w':          game.RotateRight,
+	'd':          game.RotateRight,
+	'x':          game.RotateRight,
+	'c':          game.Hold,
+	'p':          game.Paused,
+	'q':          game.Closed,
+	keyInterrupt: game.Closed,
+}
+
+var arrowMoves = map[byte]game.Move{
+	keyUp:    game.RotateRight,
+	keyDown:  game.SoftDrop,
+	keyRight: game.MoveRight,
+	keyLeft:  game.MoveLeft,
+}
+
+// inputState holds what GetInput remembers between calls
+// (escapeStart is when an escape sequence split across reads started waiting for the rest)
+type inputState struct {
+	softDropUntil time.Time
+	escapeStart   time.Time
+	closed        bool
+}
+
+// readKeys reads stdin until it ends, sending every byte to the keys channel
+func (terminal *Terminal) readKeys() {
+	buffer := make([]byte, 64)
+
+	for {
+		read, err := os.Stdin.Read(buffer)
+
+		for _, key := range buffer[:read] {
+			terminal.keys <- key
+		}
+
+		if err != nil {
+			close(terminal.keys)
+			return
+		}
+	}
+}
+
+// GetInput waits up to a frame for a key and returns the move it makes
+func (terminal *Terminal) GetInput() game.Move {
+	waiting := len(terminal.pending) == 0 || (terminal.pending[0] == keyEscape && terminal.escapeLength() == 0)
+
+	if waiting && !terminal.input.closed {
+		timer := time.NewTimer(frameDuration)
+
+		select {
+		case key, ok := <-terminal.keys:
+			terminal.receive(key, ok)
+		case <-timer.C:
+		}
+
+		timer.Stop()
+	}
+
+	terminal.drainKeys()
+
+	if terminal.input.closed && len(terminal.pending) == 0 {
+		return game.Closed
+	}
+
+	move := terminal.nextMove()
+
+	if move == game.SoftDrop {
+		terminal.input.softDropUntil = time.Now().Add(softDropHold)
+	}
+
+	if move == game.NoMove && time.Now().Before(terminal.input.softDropUntil) {
+		move = game.SoftDrop
+	}
+
+	return move
+}
+
+// Confirm asks a yes or no question, returning false if the player answers no or quits
+func (terminal *Terminal) Confirm(question string) bool {
+	terminal.out.WriteString(cursorHome + clearScreen + question + "\r\n")
+	terminal.out.Flush()
+	terminal.lastFrame = ""
+
+	defer func() {
+		terminal.out.WriteString(clearScreen)
+		terminal.out.Flush()
+	}()
+
+	for {
+		terminal.drainKeys()
+
+		for len(terminal.pending) > 0 {
+			key := terminal.pending[0]
+			terminal.pending = terminal.pending[1:]
+
+			switch toLower(key) {
+			case 'y':
+				return true
+			case 'n', 'q', keyInterrupt:
+				return false
+			}
+		}
+
+		if terminal.input.closed {
+			return false
+		}
+
+		key, ok := <-terminal.keys
+		terminal.receive(key, ok)
+	}
+}
+
+// drainKeys moves every key already read to the pending keys
+func (terminal *Terminal) drainKeys() {
+	for !terminal.input.closed {
+		select {
+		case key, ok := <-terminal.keys:
+			terminal.receive(key, ok)
+		default:
+			return
+		}
+	}
+}
+
+// receive adds a key read from the keys channel to the pending keys
+func (terminal *Terminal) receive(key byte, ok bool) {
+	if !ok {
+		terminal.input.closed = true
+		return
+	}
+
+	terminal.pending = append(terminal.pending, key)
+}
+
+// nextMove takes the next key (or escape sequence) from the pending keys and returns its move
+func (terminal *Terminal) nextMove() game.Move {
+	if len(terminal.pending) == 0 {
+		return game.NoMove
+	}
+
+	key := terminal.pending[0]
+
+	if key != keyEscape {
+		terminal.pending = terminal.pending[1:]
+		return keyMoves[toLower(key)]
+	}
+
+	length := terminal.escapeLength()
+
+	if length == 0 {
+		// the rest of the sequence may come with the next read, unless it takes too long
+		if terminal.input.escapeStart.IsZero() {
+			terminal.input.escapeStart = time.Now()
+		}
+
+		if !terminal.input.closed && time.Since(terminal.input.escapeStart) < escapeTimeout {
+			return game.NoMove
+		}
+
+		length = len(terminal.pending)
+	}
+
+	sequence := terminal.pending[:length]
+	terminal.pending = terminal.pending[length:]
+	terminal.input.escapeStart = time.Time{}
+
+	if len(sequence) != 3 {
+		return game.NoMove
+	}
+
+	return arrowMoves[sequence[2]]
+}
+
+// escapeLength returns the length of the escape sequence starting the pending keys (which start with the escape key):
+// 1 for a lone escape key, the whole control sequence up to its final byte, or 0 if it isn't complete yet
+func (terminal *Terminal) escapeLength() int {
+	if len(terminal.pending) < 2 {
+		return 0
+	}
+
+	if terminal.pending[1] != keyCSI {
+		return 1
+	}
+
+	for i := 2; i < len(terminal.pending); i++ {
+		if terminal.pending[i] >= keyFinalMin && terminal.pending[i] <= keyFinalMax {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// toLower converts an ASCII letter to lower case
+func toLower(key byte) byte {
+	if key >= 'A' && key <= 'Z' {
+		return key + 'a' - 'A'
+	}
+
+	return key
+}
@@ -0,0 +1,75 @@
+package terminal
+
+import (
+	"bufio"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+// createTestTerminal creates a terminal reading keys sent by the test instead of stdin
+func createTestTerminal() *Terminal {
+	return &Terminal{
+		out:  bufio.NewWriter(io.Discard),
+		keys: make(chan byte, 64),
+	}
+}
+
+func TestGetInput(t *testing.T) {
+	// each step sends the keys of one read, waits, then checks the move of the next GetInput
+	type step struct {
+		keys string
+		wait time.Duration
+		move game.Move
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"key", []step{{"a", 0, game.RotateLeft}}},
+		{"upper case key", []step{{"C", 0, game.Hold}}},
+		{"arrow in one read", []step{{"\x1b[D", 0, game.MoveLeft}}},
+		{"arrow split after the escape", []step{{"\x1b", 0, game.NoMove}, {"[D", 0, game.MoveLeft}}},
+		{"arrow split after the bracket", []step{{"\x1b[", 0, game.NoMove}, {"D", 0, game.MoveLeft}}},
+		{"two arrows in one read", []step{{"\x1b[C\x1b[A", 0, game.MoveRight}, {"", 0, game.RotateRight}}},
+		{"longer sequence", []step{{"\x1b[1;5D", 0, game.NoMove}, {"q", 0, game.Closed}}},
+		{"lone escape", []step{{"\x1b", 0, game.NoMove}, {"", escapeTimeout, game.NoMove}, {"a", 0, game.RotateLeft}}},
+		{"escape before a key", []step{{"\x1bx", 0, game.NoMove}, {"x", 0, game.RotateRight}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			terminal := createTestTerminal()
+
+			for i, step := range test.steps {
+				for _, key := range []byte(step.keys) {
+					terminal.keys <- key
+				}
+
+				time.Sleep(step.wait)
+
+				move := terminal.GetInput()
+				if move != step.move {
+					t.Fatalf("step %d: got %q, expected %q", i, move, step.move)
+				}
+			}
+		})
+	}
+}
+
+func TestGetInputAfterStdinCloses(t *testing.T) {
+	terminal := createTestTerminal()
+	terminal.keys <- 'd'
+	close(terminal.keys)
+
+	if move := terminal.GetInput(); move != game.RotateRight {
+		t.Fatalf("got %q, expected the key read before stdin closed", move)
+	}
+
+	if move := terminal.GetInput(); move != game.Closed {
+		t.Fatalf("got %q, expected %q", move, game.Closed)
+	}
+}
@@ -0,0 +1,260 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+	"github.com/daplf/go-tetris/io/palette"
+	"github.com/daplf/go-tetris/utils/consts"
+)
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen = "\x1b[2J"
+	cursorHome  = "\x1b[H"
+	clearLine   = "\x1b[K"
+	resetColor  = "\x1b[0m"
+	dimColor    = "\x1b[2m"
+
+	emptySquare = " ."
+	fullSquare  = "  "
+	ghostSquare = "[]"
+	hintSquare  = "<>"
+
+	borderSide    = "│"
+	borderBottom  = "──"
+	cornerLeft    = "└"
+	cornerRight   = "┘"
+	infoGap       = "   "
+	maxNextPieces = 3
+)
+
+// Terminal draws games with ANSI escape sequences and reads moves from stdin in raw mode
+type Terminal struct {
+	out       *bufio.Writer
+	sttyState string
+	trueColor bool
+	lastFrame string
+	keys      chan byte
+	pending   []byte
+	input     inputState
+}
+
+// CreateTerminal switches the terminal to raw mode and an alternate screen.
+// Colors use 24-bit escape sequences if trueColor is set, and the 256 color palette otherwise.
+func CreateTerminal(trueColor bool) (*Terminal, error) {
+	sttyState, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+
+	_, err = stty("raw", "-echo")
+	if err != nil {
+		return nil, err
+	}
+
+	terminal := &Terminal{
+		out:       bufio.NewWriter(os.Stdout),
+		sttyState: strings.TrimSpace(sttyState),
+		trueColor: trueColor,
+		keys:      make(chan byte, 64),
+	}
+
+	go terminal.readKeys()
+
+	terminal.out.WriteString(enterScreen + clearScreen)
+	terminal.out.Flush()
+
+	return terminal, nil
+}
+
+// Close restores the terminal to the state it was in before CreateTerminal
+func (terminal *Terminal) Close() error {
+	terminal.out.WriteString(resetColor + leaveScreen)
+	terminal.out.Flush()
+
+	_, err := stty(terminal.sttyState)
+
+	return err
+}
+
+// stty runs stty on the terminal attached to stdin
+func stty(args ...string) (string, error) {
+	command := exec.Command("stty", args...)
+	command.Stdin = os.Stdin
+
+	output, err := command.Output()
+
+	return string(output), err
+}
+
+// DrawFrame draws a snapshot of a game on the terminal, writing the whole frame only when it differs from the last one
+func (terminal *Terminal) DrawFrame(snapshot game.Snapshot) {
+	ghost := map[[2]block.Position]block.Type{}
+	for _, square := range snapshot.Ghost {
+		ghost[[2]block.Position{square.X, square.Y}] = square.Type
+	}
+
+	hint := map[[2]block.Position]bool{}
+	for _, square := range snapshot.Hint {
+		hint[[2]block.Position{square.X, square.Y}] = true
+	}
+
+	info := terminal.infoLines(snapshot)
+	frame := strings.Builder{}
+	frame.WriteString(cursorHome)
+
+	for y := snapshot.Height - 1; y >= 0; y-- {
+		frame.WriteString(borderSide)
+
+		for x := 0; x < snapshot.Width; x++ {
+			squareType := snapshot.Squares[y][x]
+			ghostType, isGhost := ghost[[2]block.Position{x, y}]
+
+			if squareType != piece.NoPiece {
+				frame.WriteString(terminal.background(squareType) + fullSquare + resetColor)
+			} else if hint[[2]block.Position{x, y}] {
+				frame.WriteString(hintSquare)
+			} else if isGhost {
+				frame.WriteString(terminal.foreground(ghostType) + ghostSquare + resetColor)
+			} else {
+				frame.WriteString(dimColor + emptySquare + resetColor)
+			}
+		}
+
+		frame.WriteString(borderSide)
+
+		line := snapshot.Height - 1 - y
+		if line < len(info) {
+			frame.WriteString(infoGap + info[line])
+		}
+
+		frame.WriteString(clearLine + "\r\n")
+	}
+
+	frame.WriteString(cornerLeft + strings.Repeat(borderBottom, snapshot.Width) + cornerRight + clearLine + "\r\n")
+
+	// the whole frame is written again only if something changed, as the terminal is often on the other side of a network
+	if frame.String() != terminal.lastFrame {
+		terminal.lastFrame = frame.String()
+		terminal.out.WriteString(terminal.lastFrame)
+		terminal.out.Flush()
+	}
+}
+
+// infoLines builds the info tab shown next to the board
+func (terminal *Terminal) infoLines(snapshot game.Snapshot) []string {
+	lines := []string{"Next"}
+
+	for i, pieceType := range snapshot.Queue {
+		if i >= maxNextPieces {
+			break
+		}
+
+		lines = append(lines, terminal.pieceLines(pieceType, snapshot)...)
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Hold")
+	lines = append(lines, terminal.pieceLines(snapshot.Held, snapshot)...)
+	lines = append(lines, "", fmt.Sprintf("Score %d", snapshot.Score), fmt.Sprintf("Level %d", snapshot.Level), fmt.Sprintf("Lines %d", snapshot.Lines))
+
+	if snapshot.Paused {
+		lines = append(lines, "", "Paused")
+	}
+
+	return lines
+}
+
+// pieceLines draws a piece of the given type in its spawn state, top row first
+func (terminal *Terminal) pieceLines(pieceType block.Type, snapshot game.Snapshot) []string {
+	if pieceType == piece.NoPiece || snapshot.RotationSystem == nil {
+		return []string{""}
+	}
+
+	coords := snapshot.RotationSystem.Coords(pieceType, snapshot.RotationSystem.SpawnState(pieceType))
+	minX, maxX, minY, maxY := coords[0][0], coords[0][0], coords[1][0], coords[1][0]
+
+	for i := range coords[0] {
+		if coords[0][i] < minX {
+			minX = coords[0][i]
+		}
+		if coords[0][i] > maxX {
+			maxX = coords[0][i]
+		}
+		if coords[1][i] < minY {
+			minY = coords[1][i]
+		}
+		if coords[1][i] > maxY {
+			maxY = coords[1][i]
+		}
+	}
+
+	lines := []string{}
+
+	for y := maxY; y >= minY; y-- {
+		line := strings.Builder{}
+
+		for x := minX; x <= maxX; x++ {
+			filled := false
+
+			for i := range coords[0] {
+				if coords[0][i] == x && coords[1][i] == y {
+					filled = true
+				}
+			}
+
+			if filled {
+				line.WriteString(terminal.background(pieceType) + fullSquare + resetColor)
+			} else {
+				line.WriteString(fullSquare)
+			}
+		}
+
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+// background returns the escape sequence setting the background to the color of a piece type
+func (terminal *Terminal) background(pieceType block.Type) string {
+	return terminal.color(pieceType, 48)
+}
+
+// foreground returns the escape sequence setting the foreground to the color of a piece type
+func (terminal *Terminal) foreground(pieceType block.Type) string {
+	return terminal.color(pieceType, 38)
+}
+
+// color returns the escape sequence setting a color (38 for foreground, 48 for background) to the color of a piece type
+func (terminal *Terminal) color(pieceType block.Type, target int) string {
+	r, g, b, error := palette.GetTypeColor(pieceType)
+
+	if error != consts.NoError {
+		return ""
+	}
+
+	if terminal.trueColor {
+		return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", target, palette.ToByte(r), palette.ToByte(g), palette.ToByte(b))
+	}
+
+	return fmt.Sprintf("\x1b[%d;5;%dm", target, to256Color(r, g, b))
+}
+
+// to256Color finds the closest color in the 6x6x6 cube of the 256 color palette
+func to256Color(r, g, b palette.ColorType) int {
+	return 16 + 36*toCube(r) + 6*toCube(g) + toCube(b)
+}
+
+// toCube converts a color channel to the 0-5 range of the 256 color cube
+func toCube(channel palette.ColorType) int {
+	return int(channel*5 + 0.5)
+}
@@ -0,0 +1,233 @@
+package web
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/piece"
+	"github.com/daplf/go-tetris/game/piece/block"
+	"github.com/daplf/go-tetris/io/palette"
+	"github.com/daplf/go-tetris/io/websocket"
+	"github.com/daplf/go-tetris/utils/consts"
+)
+
+const (
+	// frameDuration is how long GetInput waits for a move, which paces the game loop
+	frameDuration = time.Second / 60
+
+	messageHello = "hello"
+	messageFrame = "frame"
+
+	pagePath   = "/"
+	socketPath = "/ws"
+)
+
+//go:embed index.html
+var page []byte
+
+// inputMoves are the moves a browser is allowed to send
+var inputMoves = map[game.Move]bool{
+	game.MoveDown:    true,
+	game.MoveLeft:    true,
+	game.MoveRight:   true,
+	game.RotateLeft:  true,
+	game.RotateRight: true,
+	game.HardDrop:    true,
+	game.SoftDrop:    true,
+	game.Hold:        true,
+	game.Paused:      true,
+}
+
+// helloMessage is sent before the first frame, with what the page needs to draw pieces
+type helloMessage struct {
+	Type   string                            `json:"type"`
+	Colors map[block.Type]string             `json:"colors"`
+	Shapes map[block.Type][][]block.Position `json:"shapes"`
+}
+
+// frameMessage is sent whenever the game changes
+type frameMessage struct {
+	Type     string        `json:"type"`
+	Snapshot game.Snapshot `json:"snapshot"`
+}
+
+// inputMessage is sent by the page when a key is pressed.
+// Held moves (soft drop) are repeated on every update until they are sent again with held set to false.
+type inputMessage struct {
+	Move game.Move `json:"move"`
+	Held *bool     `json:"held,omitempty"`
+}
+
+// Session is a browser playing a game over a WebSocket, which is both its renderer and its input source.
+// done is closed when the page goes away, and stop when the game stops reading moves.
+type Session struct {
+	conn      *websocket.Conn
+	moves     chan inputMessage
+	done      chan struct{}
+	stop      chan struct{}
+	held      game.Move
+	greeted   bool
+	lastFrame []byte
+}
+
+// CreateServer creates a handler serving the page and calling play with a session for every WebSocket connection
+func CreateServer(play func(session *Session)) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(pagePath, func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != pagePath {
+			http.NotFound(writer, request)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+		writer.Write(page)
+	})
+
+	mux.HandleFunc(socketPath, func(writer http.ResponseWriter, request *http.Request) {
+		if !sameOrigin(request) {
+			http.Error(writer, "cross origin websocket", http.StatusForbidden)
+			return
+		}
+
+		conn, err := websocket.Upgrade(writer, request)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		session := &Session{
+			conn:  conn,
+			moves: make(chan inputMessage, 64),
+			done:  make(chan struct{}),
+			stop:  make(chan struct{}),
+		}
+		defer close(session.stop)
+
+		go session.readMoves()
+
+		play(session)
+	})
+
+	return mux
+}
+
+// sameOrigin checks that a WebSocket request comes from a page served by the same host
+func sameOrigin(request *http.Request) bool {
+	origin := request.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	originURL, err := url.Parse(origin)
+
+	return err == nil && originURL.Host == request.Host
+}
+
+// readMoves reads the moves sent by the page until the connection ends or the game stops reading them
+func (session *Session) readMoves() {
+	defer close(session.done)
+
+	for {
+		data, err := session.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		message := inputMessage{}
+
+		err = json.Unmarshal(data, &message)
+		if err != nil || !inputMoves[message.Move] {
+			continue
+		}
+
+		select {
+		case session.moves <- message:
+		case <-session.stop:
+			return
+		}
+	}
+}
+
+// DrawFrame sends a snapshot of the game to the page, if it changed since the last one
+func (session *Session) DrawFrame(snapshot game.Snapshot) {
+	if !session.greeted {
+		session.greeted = true
+		session.send(createHello(snapshot.RotationSystem))
+	}
+
+	data, err := json.Marshal(frameMessage{Type: messageFrame, Snapshot: snapshot})
+	if err != nil || bytes.Equal(data, session.lastFrame) {
+		return
+	}
+
+	session.lastFrame = data
+	session.conn.WriteMessage(data)
+}
+
+// GetInput waits up to a frame for a move from the page (game.Closed once the page is gone)
+func (session *Session) GetInput() game.Move {
+	timer := time.NewTimer(frameDuration)
+	defer timer.Stop()
+
+	select {
+	case message := <-session.moves:
+		if message.Held == nil {
+			return message.Move
+		}
+
+		if *message.Held {
+			session.held = message.Move
+			return message.Move
+		}
+
+		if session.held == message.Move {
+			session.held = game.NoMove
+		}
+		break
+	case <-session.done:
+		return game.Closed
+	case <-timer.C:
+		break
+	}
+
+	return session.held
+}
+
+// send sends a message as JSON
+func (session *Session) send(message interface{}) {
+	data, err := json.Marshal(message)
+	if err == nil {
+		session.conn.WriteMessage(data)
+	}
+}
+
+// createHello builds the hello message, with the palette and the spawn shape of every piece
+func createHello(rotationSystem piece.RotationSystem) helloMessage {
+	hello := helloMessage{
+		Type:   messageHello,
+		Colors: map[block.Type]string{},
+		Shapes: map[block.Type][][]block.Position{},
+	}
+
+	for _, blockType := range append([]block.Type{piece.Garbage}, piece.Types...) {
+		r, g, b, error := palette.GetTypeColor(blockType)
+		if error == consts.NoError {
+			hello.Colors[blockType] = fmt.Sprintf("#%02x%02x%02x", palette.ToByte(r), palette.ToByte(g), palette.ToByte(b))
+		}
+	}
+
+	for _, pieceType := range piece.Types {
+		if rotationSystem != nil {
+			hello.Shapes[pieceType] = rotationSystem.Coords(pieceType, rotationSystem.SpawnState(pieceType))
+		}
+	}
+
+	return hello
+}
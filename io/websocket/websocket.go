@@ -0,0 +1,242 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// acceptGUID is appended to the client's key to compute Sec-WebSocket-Accept (RFC 6455, section 1.3)
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// MaxMessageSize is the largest message a connection accepts
+	MaxMessageSize = 1 << 20
+
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+
+	finBit  = 0x80
+	maskBit = 0x80
+)
+
+var (
+	// ErrMessageTooLarge is returned when a message is larger than MaxMessageSize
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+
+	// ErrUnmaskedFrame is returned when a client sends a frame without masking it
+	ErrUnmaskedFrame = errors.New("websocket: client frame is not masked")
+)
+
+// Conn is the server side of a WebSocket connection carrying text messages
+type Conn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	writeMutex sync.Mutex
+}
+
+// Upgrade answers a WebSocket handshake and takes over the connection of the request
+func Upgrade(writer http.ResponseWriter, request *http.Request) (*Conn, error) {
+	key := request.Header.Get("Sec-WebSocket-Key")
+
+	if request.Method != http.MethodGet ||
+		!headerContains(request.Header, "Connection", "upgrade") ||
+		!headerContains(request.Header, "Upgrade", "websocket") ||
+		request.Header.Get("Sec-WebSocket-Version") != "13" ||
+		key == "" {
+		http.Error(writer, "not a websocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a websocket handshake")
+	}
+
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		http.Error(writer, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response can't be hijacked")
+	}
+
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha1.Sum([]byte(key + acceptGUID))
+
+	fmt.Fprintf(buffer, "HTTP/1.1 101 Switching Protocols\r\n")
+	fmt.Fprintf(buffer, "Upgrade: websocket\r\nConnection: Upgrade\r\n")
+	fmt.Fprintf(buffer, "Sec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(hash[:]))
+
+	err = buffer.Flush()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Conn{
+		conn:   conn,
+		reader: buffer.Reader,
+	}, nil
+}
+
+// headerContains checks if a comma separated header contains a token (ignoring case)
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ReadMessage waits for the next text or binary message, answering pings along the way.
+// It returns io.EOF once the client closes the connection.
+func (conn *Conn) ReadMessage() ([]byte, error) {
+	message := []byte{}
+
+	for {
+		fin, opcode, payload, err := conn.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			err = conn.writeFrame(opPong, payload)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			conn.writeFrame(opClose, payload)
+			return nil, io.EOF
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return nil, ErrMessageTooLarge
+		}
+
+		message = append(message, payload...)
+
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, unmasking its payload
+func (conn *Conn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+
+	_, err := io.ReadFull(conn.reader, header)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&finBit != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&maskBit != 0
+	length := uint64(header[1] & 0x7F)
+
+	if !masked {
+		return false, 0, nil, ErrUnmaskedFrame
+	}
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		_, err = io.ReadFull(conn.reader, extended)
+		length = uint64(binary.BigEndian.Uint16(extended))
+		break
+	case 127:
+		extended := make([]byte, 8)
+		_, err = io.ReadFull(conn.reader, extended)
+		length = binary.BigEndian.Uint64(extended)
+		break
+	}
+
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	if length > MaxMessageSize {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+
+	mask := make([]byte, 4)
+
+	_, err = io.ReadFull(conn.reader, mask)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+
+	_, err = io.ReadFull(conn.reader, payload)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	switch opcode {
+	case opContinuation, opText, opBinary, opClose, opPing, opPong:
+		return fin, opcode, payload, nil
+	}
+
+	return false, 0, nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+}
+
+// WriteMessage sends a text message (it is safe to call from several goroutines)
+func (conn *Conn) WriteMessage(message []byte) error {
+	return conn.writeFrame(opText, message)
+}
+
+// writeFrame writes a single unmasked frame
+func (conn *Conn) writeFrame(opcode byte, payload []byte) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+
+	header := []byte{finBit | opcode}
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		header = append(header, byte(length))
+		break
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+		break
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+		break
+	}
+
+	_, err := conn.conn.Write(append(header, payload...))
+
+	return err
+}
+
+// Close closes the connection
+func (conn *Conn) Close() error {
+	return conn.conn.Close()
+}
@@ -0,0 +1,226 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// clientFrame builds a frame the way a client sends it, masked
+func clientFrame(fin bool, opcode byte, payload []byte) []byte {
+	header := []byte{opcode, maskBit}
+	if fin {
+		header[0] |= finBit
+	}
+
+	switch {
+	case len(payload) < 126:
+		header[1] |= byte(len(payload))
+	case len(payload) <= 0xFFFF:
+		header[1] |= 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(len(payload)))
+	default:
+		header[1] |= 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(len(payload)))
+	}
+
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	frame := append(header, mask...)
+
+	for i, value := range payload {
+		frame = append(frame, value^mask[i%4])
+	}
+
+	return frame
+}
+
+// createPipe creates a server connection, and the client end of it
+func createPipe() (*Conn, net.Conn) {
+	server, client := net.Pipe()
+
+	return &Conn{conn: server, reader: bufio.NewReader(server)}, client
+}
+
+func TestReadMessage(t *testing.T) {
+	long := bytes.Repeat([]byte("x"), 300)
+
+	tests := []struct {
+		name    string
+		frames  [][]byte
+		message []byte
+		err     error
+	}{
+		{"text", [][]byte{clientFrame(true, opText, []byte("hello"))}, []byte("hello"), nil},
+		{"empty", [][]byte{clientFrame(true, opText, nil)}, []byte{}, nil},
+		{"extended length", [][]byte{clientFrame(true, opText, long)}, long, nil},
+		{
+			"fragments",
+			[][]byte{clientFrame(false, opText, []byte("hel")), clientFrame(false, opContinuation, []byte("l")), clientFrame(true, opContinuation, []byte("o"))},
+			[]byte("hello"),
+			nil,
+		},
+		{"pong between messages", [][]byte{clientFrame(true, opPong, nil), clientFrame(true, opText, []byte("hi"))}, []byte("hi"), nil},
+		{"unmasked", [][]byte{{finBit | opText, 2, 'h', 'i'}}, nil, ErrUnmaskedFrame},
+		{"too large", [][]byte{{finBit | opText, maskBit | 127, 0, 0, 0, 0, 0x10, 0, 0, 0}}, nil, ErrMessageTooLarge},
+		{"connection lost", nil, nil, io.EOF},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, client := createPipe()
+			defer conn.Close()
+
+			go func() {
+				for _, frame := range test.frames {
+					client.Write(frame)
+				}
+
+				client.Close()
+			}()
+
+			message, err := conn.ReadMessage()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got error %v, expected %v", err, test.err)
+			}
+
+			if !bytes.Equal(message, test.message) {
+				t.Fatalf("got %q, expected %q", message, test.message)
+			}
+		})
+	}
+}
+
+func TestReadMessageAnswersPingsAndCloses(t *testing.T) {
+	conn, client := createPipe()
+	defer conn.Close()
+
+	answers := make(chan []byte, 2)
+
+	go func() {
+		reader := bufio.NewReader(client)
+
+		for _, frame := range [][]byte{clientFrame(true, opPing, []byte("ping")), clientFrame(true, opClose, nil)} {
+			client.Write(frame)
+
+			header := make([]byte, 2)
+			io.ReadFull(reader, header)
+			payload := make([]byte, header[1])
+			io.ReadFull(reader, payload)
+
+			answers <- append(header, payload...)
+		}
+	}()
+
+	_, err := conn.ReadMessage()
+	if err != io.EOF {
+		t.Fatalf("got error %v, expected the close to end the connection", err)
+	}
+
+	pong, closed := <-answers, <-answers
+
+	if !bytes.Equal(pong, []byte{finBit | opPong, 4, 'p', 'i', 'n', 'g'}) {
+		t.Fatalf("got %v, expected a pong with the ping's payload", pong)
+	}
+
+	if !bytes.Equal(closed, []byte{finBit | opClose, 0}) {
+		t.Fatalf("got %v, expected a close frame", closed)
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	tests := []struct {
+		length int
+		header []byte
+	}{
+		{5, []byte{finBit | opText, 5}},
+		{125, []byte{finBit | opText, 125}},
+		{126, []byte{finBit | opText, 126, 0, 126}},
+		{0x10000, []byte{finBit | opText, 127, 0, 0, 0, 0, 0, 1, 0, 0}},
+	}
+
+	for _, test := range tests {
+		conn, client := createPipe()
+		message := bytes.Repeat([]byte("y"), test.length)
+
+		go func() {
+			conn.WriteMessage(message)
+			conn.Close()
+		}()
+
+		frame, err := io.ReadAll(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(frame[:len(test.header)], test.header) || !bytes.Equal(frame[len(test.header):], message) {
+			t.Fatalf("%d bytes: got header %v, expected %v", test.length, frame[:len(test.header)], test.header)
+		}
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	messages := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, err := Upgrade(writer, request)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		message, err := conn.ReadMessage()
+		if err == nil {
+			messages <- string(message)
+		}
+	}))
+	defer server.Close()
+
+	client, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// the handshake from RFC 6455, section 1.3
+	request := "GET / HTTP/1.1\r\nHost: localhost\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n"
+
+	_, err = client.Write([]byte(request))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := http.ReadResponse(bufio.NewReader(client), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.StatusCode != http.StatusSwitchingProtocols || response.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("got status %d and accept %q", response.StatusCode, response.Header.Get("Sec-WebSocket-Accept"))
+	}
+
+	client.Write(clientFrame(true, opText, []byte("move")))
+
+	if message := <-messages; message != "move" {
+		t.Fatalf("got %q, expected %q", message, "move")
+	}
+
+	// a plain request is refused
+	plain, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain.Body.Close()
+
+	if plain.StatusCode != http.StatusBadRequest {
+		t.Fatalf("got status %d for a plain request", plain.StatusCode)
+	}
+}
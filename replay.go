@@ -0,0 +1,59 @@
+//go:build !nogl
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/replay"
+	"github.com/daplf/go-tetris/io/inputProcessor"
+	"github.com/daplf/go-tetris/io/renderer"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// runReplay plays a recorded game back in a window
+func runReplay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := flags.Float64("speed", 1, "playback speed (2 plays twice as fast)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go-tetris replay [-speed n] file")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if *speed <= 0 {
+		fmt.Fprintln(os.Stderr, "the playback speed must be above 0")
+		os.Exit(2)
+	}
+
+	recording, err := replay.Load(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	pixelgl.Run(func() {
+		player := replay.CreatePlayer(recording)
+		renderer := renderer.CreateRenderer()
+		inputProcessor := inputProcessor.CreateInputProcessor(renderer.Window())
+		start := time.Now()
+
+		for inputProcessor.GetInput() != game.Closed {
+			player.AdvanceTo(time.Duration(float64(time.Since(start)) * *speed))
+			renderer.DrawFrame(player.Game().Snapshot())
+		}
+
+		if player.Done() && !player.Matches() {
+			fmt.Fprintln(os.Stderr, "replay diverged from the recorded game")
+		}
+	})
+}
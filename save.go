@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+const saveFile = "save.json"
+
+// savedGame is a resumed game, driven by its own clock so every update happens at an exact time.
+// The clock only starts once play resumes, so the time spent deciding to resume isn't played.
+type savedGame struct {
+	game    *game.Game
+	clock   *game.ManualClock
+	start   time.Time
+	resumed time.Time
+}
+
+// savePath returns where an unfinished game is saved between runs
+func savePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "go-tetris", saveFile), nil
+}
+
+// loadSavedGame restores the saved game, if there is one
+func loadSavedGame() (*savedGame, error) {
+	path, err := savePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	clock := game.CreateManualClock(start)
+
+	restored, err := game.RestoreGame(data, clock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &savedGame{game: restored, clock: clock, start: start}, nil
+}
+
+// Update moves the game's clock by the time played since the first update, and updates the game
+func (saved *savedGame) Update(move game.Move) {
+	if saved.resumed.IsZero() {
+		saved.resumed = time.Now()
+	}
+
+	saved.clock.Set(saved.start.Add(time.Since(saved.resumed)))
+	saved.game.Update(move)
+}
+
+// saveGame saves an unfinished game so it can be resumed on the next run
+func saveGame(game *game.Game) error {
+	path, err := savePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(game)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// removeSavedGame deletes the saved game, if there is one
+func removeSavedGame() error {
+	path, err := savePath()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
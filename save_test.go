@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daplf/go-tetris/game"
+)
+
+// lowestRow returns the row of the current piece's lowest block
+func lowestRow(current *game.Game) int {
+	row := current.Board().Height()
+
+	for _, pieceBlock := range current.CurrentPiece().Blocks() {
+		if pieceBlock.Y() < row {
+			row = pieceBlock.Y()
+		}
+	}
+
+	return row
+}
+
+func TestSaveAndResume(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	saved, err := loadSavedGame()
+	if saved != nil || err != nil {
+		t.Fatalf("got %v and %v without a save, expected nothing", saved, err)
+	}
+
+	config := game.DefaultConfig()
+	config.Seed = 1
+	config.Clock = game.CreateManualClock(time.Unix(0, 0))
+	config.GravityCurve = []time.Duration{10 * time.Millisecond}
+
+	original := game.CreateGameWithConfig(config)
+	original.Update(game.HardDrop)
+
+	err = saveGame(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err = loadSavedGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := lowestRow(saved.game)
+
+	// the player takes a while to answer the resume prompt, which would be enough for the piece to fall to the floor
+	time.Sleep(250 * time.Millisecond)
+	saved.Update(game.NoMove)
+
+	if lowestRow(saved.game) < row-1 {
+		t.Fatalf("the piece fell from row %d to %d while the game waited to resume", row, lowestRow(saved.game))
+	}
+
+	err = removeSavedGame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err = loadSavedGame()
+	if saved != nil || err != nil {
+		t.Fatalf("got %v and %v after removing the save, expected nothing", saved, err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/io/web"
+)
+
+// runServe serves a page playing games in the browser, each connection playing its own game on the server
+func runServe(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	address := flags.String("addr", "localhost:8080", "address to listen on")
+	flags.Parse(args)
+
+	server := web.CreateServer(func(session *web.Session) {
+		current := game.CreateGame()
+
+		for current.IsRunning() {
+			session.DrawFrame(current.Snapshot())
+			current.Update(session.GetInput())
+		}
+
+		session.DrawFrame(current.Snapshot())
+	})
+
+	fmt.Printf("open http://%s to play\n", *address)
+
+	err := http.ListenAndServe(*address, server)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
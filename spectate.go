@@ -0,0 +1,67 @@
+//go:build !nogl
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+	"github.com/daplf/go-tetris/io/network"
+	"github.com/daplf/go-tetris/io/renderer"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// runSpectate watches a match on a server, or a game shared with -spectate
+func runSpectate(args []string) {
+	flags := flag.NewFlagSet("spectate", flag.ExitOnError)
+	room := flags.String("room", "default", "room to watch (ignored for shared games)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: go-tetris spectate [-room name] [host:port]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	address := "localhost:" + network.DefaultPort
+	if flags.NArg() > 0 {
+		address = flags.Arg(0)
+	}
+
+	spectator, err := network.Spectate(address, *room)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer spectator.Close()
+
+	games := spectator.Games()
+	if len(games) != 1 && len(games) != versus.Players {
+		fmt.Fprintf(os.Stderr, "can't show %d games\n", len(games))
+		os.Exit(1)
+	}
+
+	pixelgl.Run(func() {
+		renderer := createSpectatorRenderer(len(games))
+
+		for !renderer.Window().Closed() {
+			spectator.Update()
+
+			if len(games) == 1 {
+				renderer.DrawFrame(games[0].Snapshot())
+			} else {
+				renderer.DrawVersus([versus.Players]game.Snapshot{games[0].Snapshot(), games[1].Snapshot()}, spectator.Winner())
+			}
+		}
+	})
+}
+
+// createSpectatorRenderer creates a renderer wide enough for the number of games watched
+func createSpectatorRenderer(games int) *renderer.Renderer {
+	if games == 1 {
+		return renderer.CreateRenderer()
+	}
+
+	return renderer.CreateVersusRenderer()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/daplf/go-tetris/io/terminal"
+)
+
+// runTerminal plays a game in the terminal
+func runTerminal(args []string) {
+	colorTerm := os.Getenv("COLORTERM")
+
+	flags := flag.NewFlagSet("terminal", flag.ExitOnError)
+	recordPath := flags.String("record", "", "write a replay of the game to this file")
+	spectateAddress := flags.String("spectate", "", "let spectators watch the game on this address (for example :7778)")
+	demo := flags.Bool("demo", false, "let the AI play a new game (pause or quit as usual)")
+	hint := flags.Bool("hint", false, "show where the AI would place the current piece")
+	weightsPath := flags.String("weights", "", "file with the AI's weights, as written by the tune command (default weights if empty)")
+	trueColor := flags.Bool("truecolor", colorTerm == "truecolor" || colorTerm == "24bit", "use 24-bit colors instead of the 256 color palette")
+	flags.Parse(args)
+
+	weights := loadWeights(*weightsPath)
+
+	saved, err := loadSavedGame()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not load saved game:", err)
+	}
+
+	// demos play a new game, leaving the saved one for later
+	if *demo {
+		saved = nil
+	}
+
+	terminal, err := terminal.CreateTerminal(*trueColor)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer terminal.Close()
+
+	if saved != nil && !terminal.Confirm("Resume saved game? (y/n)") {
+		saved = nil
+	}
+
+	playGame(saved, playOptions{recordPath: *recordPath, spectateAddress: *spectateAddress, demo: *demo, hint: *hint, weights: weights}, terminal, terminal)
+}
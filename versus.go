@@ -0,0 +1,40 @@
+//go:build !nogl
+
+package main
+
+import (
+	"github.com/daplf/go-tetris/game"
+	"github.com/daplf/go-tetris/game/versus"
+	"github.com/daplf/go-tetris/io/inputProcessor"
+	"github.com/daplf/go-tetris/io/renderer"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// runVersus plays a two player game in a window, showing the result until the window is closed
+func runVersus(args []string) {
+	pixelgl.Run(func() {
+		renderer := renderer.CreateVersusRenderer()
+		inputProcessor := inputProcessor.CreateInputProcessor(renderer.Window())
+		match := versus.CreateVersus(game.DefaultConfig())
+
+		for match.IsRunning() {
+			renderer.DrawVersus(versusSnapshots(match), match.Winner())
+			match.Update(inputProcessor.GetVersusInput())
+		}
+
+		for !renderer.Window().Closed() {
+			renderer.DrawVersus(versusSnapshots(match), match.Winner())
+		}
+	})
+}
+
+// versusSnapshots takes a snapshot of the game of each player
+func versusSnapshots(match *versus.Versus) [versus.Players]game.Snapshot {
+	snapshots := [versus.Players]game.Snapshot{}
+
+	for player, current := range match.Games() {
+		snapshots[player] = current.Snapshot()
+	}
+
+	return snapshots
+}
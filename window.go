@@ -0,0 +1,74 @@
+//go:build !nogl
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/daplf/go-tetris/io/inputProcessor"
+	"github.com/daplf/go-tetris/io/renderer"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// init registers the modes that need a window
+func init() {
+	modes["replay"] = runReplay
+	modes["versus"] = runVersus
+	modes["connect"] = runConnect
+	modes["spectate"] = runSpectate
+	defaultMode = runGame
+}
+
+// runGame plays a game in a window
+func runGame(args []string) {
+	flags := flag.NewFlagSet("go-tetris", flag.ExitOnError)
+	recordPath := flags.String("record", "", "write a replay of the game to this file")
+	spectateAddress := flags.String("spectate", "", "let spectators watch the game on this address (for example :7778)")
+	demo := flags.Bool("demo", false, "let the AI play a new game (pause or quit as usual)")
+	hint := flags.Bool("hint", false, "show where the AI would place the current piece")
+	weightsPath := flags.String("weights", "", "file with the AI's weights, as written by the tune command (default weights if empty)")
+	flags.Parse(args)
+
+	weights := loadWeights(*weightsPath)
+
+	saved, err := loadSavedGame()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "could not load saved game:", err)
+	}
+
+	// demos play a new game, leaving the saved one for later
+	if *demo {
+		saved = nil
+	}
+
+	pixelgl.Run(func() {
+		renderer := renderer.CreateRenderer()
+		inputProcessor := inputProcessor.CreateInputProcessor(renderer.Window())
+
+		if saved != nil && !askResume(renderer, inputProcessor) {
+			saved = nil
+		}
+
+		if renderer.Window().Closed() {
+			return
+		}
+
+		playGame(saved, playOptions{recordPath: *recordPath, spectateAddress: *spectateAddress, demo: *demo, hint: *hint, weights: weights}, renderer, inputProcessor)
+	})
+}
+
+// askResume asks whether to resume the saved game, returning false if the window is closed instead
+func askResume(renderer *renderer.Renderer, input *inputProcessor.InputProcessor) bool {
+	for {
+		renderer.DrawPrompt("Resume saved game? (Y/N)")
+
+		switch input.GetAnswer() {
+		case inputProcessor.AnswerYes:
+			return true
+		case inputProcessor.AnswerNo, inputProcessor.AnswerClosed:
+			return false
+		}
+	}
+}